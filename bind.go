@@ -0,0 +1,196 @@
+package flags
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Bind defines a flag for each exported field of the struct that p points
+// to, storing the value of the flag in the field. The field tags set the
+// properties of the flags:
+//
+//	flag:"port"            name of the flag, or "-" to skip the field
+//	alias:"p"              single-character alias
+//	usage:"listen `port`"  help message
+//	default:"8080"         default value, parsed with Set; otherwise the field's value
+//	env:"PORT,APP_PORT"    environment variables, as with EnvVars
+//	required:"true"        make the flag required, as with Required
+//
+// Without a flag tag, the name is the field name in lower case with words
+// separated by hyphens, so that ListenAddr becomes "listen-addr".
+//
+// A field may have any type with a flag constructor in this package,
+// including slices and map[string]T, a pointer type implementing Value, or
+// a type whose pointer implements encoding.TextUnmarshaler. The fields of
+// a nested struct of any other type are bound in turn, with names prefixed
+// with the name of the struct field and a dot, so that Port in a field
+// Server becomes "server.port". The fields of an embedded struct without
+// a flag tag are bound without a prefix.
+//
+// As Var does for duplicate names, Bind panics if p is not a pointer to a
+// struct, if a tag is invalid, or if a field has an unsupported type.
+func (f *FlagSet) Bind(p interface{}) {
+	v := reflect.ValueOf(p)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		f.bindPanic(fmt.Sprintf("cannot bind %T: not a pointer to a struct", p))
+	}
+	f.bind(v.Elem(), "")
+}
+
+// Bind defines a command-line flag for each exported field of the struct
+// that p points to. See FlagSet.Bind.
+func Bind(p interface{}) {
+	CommandLine.Bind(p)
+}
+
+func (f *FlagSet) bindPanic(msg string) {
+	if f.name != "" {
+		msg = f.name + " " + msg
+	}
+	fmt.Fprintln(f.Output(), msg)
+	panic(msg) // Happens only if the struct is declared incorrectly
+}
+
+func (f *FlagSet) bind(v reflect.Value, prefix string) {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		tag := field.Tag
+		name, ok := tag.Lookup("flag")
+		if name == "-" {
+			continue
+		}
+		if !ok {
+			name = kebabCase(field.Name)
+		}
+		if name == "" {
+			f.bindPanic(fmt.Sprintf("field %s: empty flag name", field.Name))
+		}
+		name = prefix + name
+
+		addr := v.Field(i).Addr()
+		value := bindValue(addr)
+		if value == nil {
+			if field.Type.Kind() == reflect.Struct {
+				if field.Anonymous && !ok {
+					f.bind(v.Field(i), prefix)
+				} else {
+					f.bind(v.Field(i), name+".")
+				}
+				continue
+			}
+			f.bindPanic(fmt.Sprintf("field %s: unsupported type %s", field.Name, field.Type))
+		}
+
+		var alias rune
+		if s := tag.Get("alias"); s != "" {
+			r, n := utf8.DecodeRuneInString(s)
+			if n != len(s) {
+				f.bindPanic(fmt.Sprintf("field %s: invalid alias %q", field.Name, s))
+			}
+			alias = r
+		}
+		if s, ok := tag.Lookup("default"); ok {
+			if err := value.Set(s); err != nil {
+				f.bindPanic(fmt.Sprintf("field %s: invalid default %q: %v", field.Name, s, err))
+			}
+			if d, ok := value.(interface{ keepAsDefault() }); ok {
+				d.keepAsDefault()
+			}
+		}
+		var opts []Option
+		if s := tag.Get("env"); s != "" {
+			opts = append(opts, EnvVars(strings.Split(s, ",")...))
+		}
+		if s := tag.Get("required"); s != "" {
+			required, err := strconv.ParseBool(s)
+			if err != nil {
+				f.bindPanic(fmt.Sprintf("field %s: invalid required %q", field.Name, s))
+			}
+			if required {
+				opts = append(opts, Required())
+			}
+		}
+		f.Var(value, name, alias, tag.Get("usage"), nil, opts...)
+	}
+}
+
+// bindValue returns the Value that stores into the variable addr points
+// to, or nil if its type is not supported.
+func bindValue(addr reflect.Value) Value {
+	p := addr.Interface()
+	if v, ok := p.(Value); ok {
+		return v
+	}
+	if v := newScalarValue(p); v != nil {
+		return v
+	}
+	switch p := p.(type) {
+	case *[]string:
+		return newStringSliceValue(*p, p)
+	case *[]int:
+		return newIntSliceValue(*p, p)
+	case *[]float64:
+		return newFloat64SliceValue(*p, p)
+	case *[]time.Duration:
+		return newDurationSliceValue(*p, p)
+	case encoding.TextUnmarshaler:
+		return &textValue{p: addr}
+	}
+	if typ := addr.Type().Elem(); typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String &&
+		newScalarValue(reflect.New(typ.Elem()).Interface()) != nil {
+		if addr.Elem().IsNil() {
+			addr.Elem().Set(reflect.MakeMap(typ))
+		}
+		return newMapValue(p)
+	}
+	return nil
+}
+
+// kebabCase returns name in lower case with hyphens between words.
+func kebabCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteByte('-')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// -- encoding.TextUnmarshaler Value
+type textValue struct {
+	p reflect.Value // pointer to the variable
+}
+
+func (t *textValue) Set(s string) error {
+	return t.p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+}
+
+func (t *textValue) Get() interface{} { return t.p.Elem().Interface() }
+
+func (t *textValue) String() string {
+	if !t.p.IsValid() {
+		return ""
+	}
+	if m, ok := t.p.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+	return fmt.Sprint(t.p.Elem().Interface())
+}
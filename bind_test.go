@@ -0,0 +1,119 @@
+package flags_test
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/saihon/flags"
+)
+
+type Logging struct {
+	Verbose bool `alias:"v" usage:"verbose output"`
+}
+
+type bindConfig struct {
+	Logging
+	Port       int           `flag:"port" alias:"p" usage:"listen port" default:"8080" env:"BIND_TEST_PORT"`
+	ListenAddr string        `usage:"listen address"`
+	Timeout    time.Duration `default:"5s"`
+	Tags       []string      `default:"a,b"`
+	Labels     map[string]string
+	IP         net.IP `flag:"ip"`
+	Level      levelValue
+	Server     struct {
+		Name string `required:"true"`
+		TLS  struct {
+			Cert string
+		} `flag:"tls"`
+	}
+	Skipped string `flag:"-"`
+	hidden  string
+}
+
+type levelValue struct{ n int }
+
+func (l *levelValue) String() string { return strings.Repeat("*", l.n) }
+
+func (l *levelValue) Set(s string) error {
+	l.n = len(s)
+	return nil
+}
+
+func TestBind(t *testing.T) {
+	setenv(t, "BIND_TEST_PORT", "9000")
+	var cfg bindConfig
+	cfg.ListenAddr = "localhost"
+	fs := NewFlagSet("bind test", ContinueOnError, false)
+	fs.Bind(&cfg)
+
+	var names []string
+	fs.VisitAll(func(flag *Flag) { names = append(names, flag.Name) })
+	want := []string{"ip", "labels", "level", "listen-addr", "port", "server.name", "server.tls.cert", "tags", "timeout", "verbose"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("flags = %q; want %q", names, want)
+	}
+	if flag := fs.Lookup("port"); flag.DefValue != "8080" || flag.Alias != 'p' || flag.Usage != "listen port" {
+		t.Errorf("port flag = %+v", flag)
+	}
+
+	args := []string{"-v", "--tags=c", "--labels", "env=prod", "--ip", "10.0.0.1",
+		"--level", "xxx", "--server.name", "web", "--server.tls.cert", "cert.pem"}
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Verbose || cfg.Port != 9000 || cfg.ListenAddr != "localhost" || cfg.Timeout != 5*time.Second {
+		t.Errorf("got %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Tags, []string{"c"}) || !reflect.DeepEqual(cfg.Labels, map[string]string{"env": "prod"}) {
+		t.Errorf("got tags=%q labels=%v", cfg.Tags, cfg.Labels)
+	}
+	if !cfg.IP.Equal(net.ParseIP("10.0.0.1")) || cfg.Level.n != 3 || cfg.Server.Name != "web" || cfg.Server.TLS.Cert != "cert.pem" {
+		t.Errorf("got ip=%v level=%d server=%+v", cfg.IP, cfg.Level.n, cfg.Server)
+	}
+}
+
+func TestBindRequired(t *testing.T) {
+	var cfg bindConfig
+	fs := NewFlagSet("bind test", ContinueOnError, false)
+	fs.SetOutput(&bytes.Buffer{})
+	fs.Bind(&cfg)
+	if err := fs.Parse(nil); err == nil || !strings.Contains(err.Error(), "--server.name") {
+		t.Errorf("Parse() = %v; want required flag error", err)
+	}
+}
+
+func TestBindInvalid(t *testing.T) {
+	data := []interface{}{
+		bindConfig{},
+		&struct {
+			A int `alias:"ab"`
+		}{},
+		&struct {
+			A int `default:"x"`
+		}{},
+		&struct {
+			A int `required:"maybe"`
+		}{},
+		&struct{ C chan int }{},
+		&struct {
+			A int `flag:"a"`
+			B int `flag:"a"`
+		}{},
+	}
+	for _, p := range data {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Bind(%T) did not panic", p)
+				}
+			}()
+			fs := NewFlagSet("bind test", ContinueOnError, false)
+			fs.SetOutput(&bytes.Buffer{})
+			fs.Bind(p)
+		}()
+	}
+}
@@ -0,0 +1,291 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// A Command is a node of a command tree, as in "prog remote add <url>".
+// Each command has its own flag set. Execute on the root command parses
+// the flags of each level, finds the next command by the first remaining
+// argument, and runs the last command found with the arguments after it.
+type Command struct {
+	Name    string   // name as it appears on the command line
+	Aliases []string // other names accepted on the command line
+	Short   string   // one-line description shown in the list of commands
+	Long    string   // description shown in the usage message of the command
+
+	// Flags holds the flags of the command. It is created by NewCommand
+	// with ContinueOnError, and stops at the first non-flag argument once
+	// the command has subcommands.
+	Flags *FlagSet
+
+	// Run is called by Execute with the remaining arguments when the
+	// command is the last one on the command line. A command without Run
+	// requires a subcommand.
+	Run func(cmd *Command, args []string) error
+
+	// EnablePlugins makes Execute run an external program for a subcommand
+	// that is not built in: for "prog foo args", the executable prog-foo
+	// found in PluginPath, with the arguments after the subcommand. The
+	// values of the flags named in PluginFlags, defined by the command or
+	// inherited, are passed in environment variables named after the root
+	// command and the flag, such as PROG_VERBOSE for --verbose. If the
+	// plugin exits with a non-zero status, Execute returns a *PluginError
+	// whose Code holds the status, or exits with it if Flags uses
+	// ExitOnError.
+	EnablePlugins bool
+
+	PluginPath  []string // absolute directories searched for plugins; nil means $PATH
+	PluginFlags []string // names of the flags passed to plugins
+
+	// DisableHelpCommand turns off the implicit help subcommand, by which
+	// "prog help" prints the usage message of prog and "prog help sub"
+	// that of its subcommand sub, unless a subcommand named help exists.
+	DisableHelpCommand bool
+
+	parent   *Command
+	commands []*Command
+}
+
+// NewCommand returns a new command with the specified name, short
+// description and Run function, which may be nil.
+func NewCommand(name, short string, run func(cmd *Command, args []string) error) *Command {
+	c := &Command{Name: name, Short: short, Run: run}
+	c.Flags = NewFlagSet(name, ContinueOnError, false)
+	c.Flags.Usage = c.Usage
+	return c
+}
+
+// AddCommand adds subcommands to c. The flag sets of the subcommands
+// inherit the flags of c, as with SetParent, so that "prog --verbose sub"
+// and "prog sub --verbose" are equivalent. It panics if a name or alias is
+// already used by another subcommand of c, or if a flag of a subcommand
+// collides with an inherited one.
+func (c *Command) AddCommand(cmds ...*Command) {
+	for _, cmd := range cmds {
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			if c.find(name) != nil {
+				msg := fmt.Sprintf("%s command redefined: %s", c.CommandPath(), name)
+				fmt.Fprintln(c.Flags.Output(), msg)
+				panic(msg) // Happens only if commands are declared with identical names
+			}
+		}
+		cmd.Flags.SetParent(c.Flags)
+		cmd.parent = c
+		c.commands = append(c.commands, cmd)
+	}
+	c.Flags.StopImmediate = true
+}
+
+// Commands returns the subcommands of c in the order they were added.
+func (c *Command) Commands() []*Command {
+	return c.commands
+}
+
+// Parent returns the command of which c is a subcommand, or nil.
+func (c *Command) Parent() *Command {
+	return c.parent
+}
+
+// CommandPath returns the names of c and the commands above it, separated
+// by spaces, as in "prog remote add".
+func (c *Command) CommandPath() string {
+	if c.parent == nil {
+		return c.Name
+	}
+	return c.parent.CommandPath() + " " + c.Name
+}
+
+// find returns the subcommand named name, or nil.
+func (c *Command) find(name string) *Command {
+	for _, cmd := range c.commands {
+		if cmd.Name == name {
+			return cmd
+		}
+		for _, alias := range cmd.Aliases {
+			if alias == name {
+				return cmd
+			}
+		}
+	}
+	return nil
+}
+
+// A CommandError reports an unknown or missing subcommand.
+type CommandError struct {
+	Command *Command // the command whose subcommand is unknown or missing
+	Name    string   // the unknown name, or empty if none was given
+	Valid   []string // names of the subcommands and plugins of Command
+}
+
+// newCommandError returns a CommandError for the subcommand name of c,
+// listing the valid names as found now.
+func newCommandError(c *Command, name string) *CommandError {
+	valid := make([]string, len(c.commands))
+	for i, cmd := range c.commands {
+		valid[i] = cmd.Name
+	}
+	if c.EnablePlugins {
+		valid = append(valid, c.Plugins()...)
+	}
+	return &CommandError{Command: c, Name: name, Valid: valid}
+}
+
+func (e *CommandError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("missing command for %q; valid commands: %s", e.Command.CommandPath(), strings.Join(e.Valid, ", "))
+	}
+	return fmt.Sprintf("unknown command %q for %q; valid commands: %s", e.Name, e.Command.CommandPath(), strings.Join(e.Valid, ", "))
+}
+
+// Execute parses args, which should not include the program name, with
+// the flags of c and then of each subcommand named on the command line,
+// and calls Run of the last command found with the remaining arguments.
+// The flags of each level take their values from the environment and
+// other layers, and have their required flags and constraints checked,
+// only once the arguments of the last command have been parsed, so that
+// inherited flags may also be given after a subcommand.
+//
+// Execute returns the error of Parse, ErrHelp included, a *CommandError
+// for an unknown or missing command, handled like a parse error of the
+// flags of the command, a *PluginError if a plugin failed, or the error
+// of Run. With ExitOnError, a plugin exiting with a non-zero status makes
+// the program exit with the same status; see PluginError.
+func (c *Command) Execute(args []string) error {
+	cmd := c
+	for {
+		if cmd.EnablePlugins {
+			cmd.Flags.StopImmediate = true
+		}
+		if err := cmd.Flags.parseArgs(args); err != nil {
+			return err
+		}
+		args = cmd.Flags.Args()
+		if len(cmd.commands) == 0 && !cmd.EnablePlugins || len(args) == 0 && cmd.Run != nil {
+			break
+		}
+		if len(args) > 0 && args[0] == "help" && cmd.hasHelpCommand() {
+			return cmd.help(args[1:])
+		}
+		var sub *Command
+		if len(args) > 0 {
+			sub = cmd.find(args[0])
+			if sub == nil && cmd.EnablePlugins {
+				if path := cmd.lookPlugin(args[0]); path != "" {
+					if err := c.finish(cmd); err != nil {
+						return err
+					}
+					return cmd.runPlugin(args[0], path, args[1:])
+				}
+			}
+		}
+		if sub == nil {
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			return cmd.Flags.handleError(cmd.Flags.fail(newCommandError(cmd, name)))
+		}
+		cmd, args = sub, args[1:]
+	}
+	if err := c.finish(cmd); err != nil {
+		return err
+	}
+	if cmd.Run == nil {
+		return errors.New(cmd.CommandPath() + ": command has nothing to run")
+	}
+	return cmd.Run(cmd, args)
+}
+
+// finish completes the parsing of the flag sets from c down to its
+// descendant last, once the arguments of last have been parsed, so that
+// the flags of every level see the inherited flags given after a
+// subcommand when taking values from the environment and checking
+// required flags and constraints.
+func (c *Command) finish(last *Command) error {
+	var path []*Command
+	for cmd := last; cmd != c; cmd = cmd.parent {
+		path = append(path, cmd)
+	}
+	path = append(path, c)
+	for i := len(path) - 1; i >= 0; i-- {
+		if err := path[i].Flags.finish(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Usage prints the usage message of the command: its description, its
+// subcommands and its flags.
+func (c *Command) Usage() {
+	w := c.Flags.Output()
+	args := " [flags]"
+	if len(c.commands) > 0 || c.EnablePlugins {
+		args += " <command>"
+	}
+	fmt.Fprintf(w, "\nUsage: %s%s\n", c.CommandPath(), args)
+	if s := c.Long; s != "" || c.Short != "" {
+		if s == "" {
+			s = c.Short
+		}
+		fmt.Fprintf(w, "\n%s\n", strings.TrimRight(s, "\n"))
+	}
+	if len(c.commands) > 0 {
+		fmt.Fprintf(w, "\nCommands:\n")
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, cmd := range c.commands {
+			name := strings.Join(append([]string{cmd.Name}, cmd.Aliases...), ", ")
+			fmt.Fprintf(tw, "  %s\t%s\n", name, cmd.Short)
+		}
+		tw.Flush()
+		if c.hasHelpCommand() {
+			fmt.Fprintf(w, "\nUse \"%s help <command>\" for more information about a command.\n", c.CommandPath())
+		}
+	}
+	if c.EnablePlugins {
+		if plugins := c.Plugins(); len(plugins) > 0 {
+			fmt.Fprintf(w, "\nPlugins:\n")
+			for _, name := range plugins {
+				fmt.Fprintf(w, "  %s\n", name)
+			}
+		}
+	}
+	if len(c.Flags.formal) > 0 {
+		fmt.Fprintf(w, "\nFlags:\n")
+	}
+	c.Flags.PrintDefaults()
+	c.Flags.printConstraints()
+}
+
+// hasHelpCommand reports whether c has the implicit help subcommand.
+func (c *Command) hasHelpCommand() bool {
+	return !c.DisableHelpCommand && len(c.commands) > 0 && c.find("help") == nil
+}
+
+// help prints the usage message of the subcommand of c named by the path
+// of names, running a plugin with --help if it names one, and returns
+// ErrHelp.
+func (c *Command) help(names []string) error {
+	cmd := c
+	for i, name := range names {
+		sub := cmd.find(name)
+		if sub == nil && cmd.EnablePlugins && i == len(names)-1 {
+			if path := cmd.lookPlugin(name); path != "" {
+				if err := cmd.runPlugin(name, path, []string{"--help"}); err != nil {
+					return err
+				}
+				return ErrHelp
+			}
+		}
+		if sub == nil {
+			return cmd.Flags.handleError(cmd.Flags.fail(newCommandError(cmd, name)))
+		}
+		cmd = sub
+	}
+	cmd.Usage()
+	return ErrHelp
+}
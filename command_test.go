@@ -0,0 +1,120 @@
+package flags_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+func newTestTree(ran *string, gotArgs *[]string) (*Command, *bool, *bool) {
+	run := func(cmd *Command, args []string) error {
+		*ran = cmd.CommandPath()
+		*gotArgs = args
+		return nil
+	}
+	root := NewCommand("prog", "a test program", nil)
+	verbose := root.Flags.Bool("verbose", 'v', false, "verbose output", nil)
+
+	remote := NewCommand("remote", "manage remotes", run)
+	add := NewCommand("add", "add a remote", run)
+	add.Aliases = []string{"a"}
+	fetch := add.Flags.Bool("fetch", 'f', false, "fetch after adding", nil)
+	remote.AddCommand(add, NewCommand("remove", "remove a remote", run))
+
+	root.AddCommand(remote, NewCommand("status", "show status", run))
+	return root, verbose, fetch
+}
+
+func TestCommandExecute(t *testing.T) {
+	data := []struct {
+		args    []string
+		ran     string
+		rest    []string
+		verbose bool
+		fetch   bool
+	}{
+		{args: []string{"status"}, ran: "prog status", rest: []string{}},
+		{args: []string{"-v", "remote", "add", "origin", "-f", "url"}, ran: "prog remote add", rest: []string{"origin", "url"}, verbose: true, fetch: true},
+		{args: []string{"remote", "a", "origin"}, ran: "prog remote add", rest: []string{"origin"}},
+		{args: []string{"remote"}, ran: "prog remote", rest: []string{}},
+	}
+
+	for _, v := range data {
+		var ran string
+		var rest []string
+		root, verbose, fetch := newTestTree(&ran, &rest)
+		if err := root.Execute(v.args); err != nil {
+			t.Errorf("%q: %v", v.args, err)
+			continue
+		}
+		if ran != v.ran || !reflect.DeepEqual(rest, v.rest) || *verbose != v.verbose || *fetch != v.fetch {
+			t.Errorf("%q: ran %q with %q verbose=%t fetch=%t", v.args, ran, rest, *verbose, *fetch)
+		}
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	data := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"stats"}, want: `unknown command "stats" for "prog"; valid commands: remote, status`},
+		{args: []string{"remote", "rename"}, want: `unknown command "rename" for "prog remote"; valid commands: add, remove`},
+		{args: nil, want: `missing command for "prog"; valid commands: remote, status`},
+		{args: []string{"status", "--bogus"}, want: "flag provided but not defined: --bogus"},
+	}
+
+	for _, v := range data {
+		var ran string
+		var rest []string
+		root, _, _ := newTestTree(&ran, &rest)
+		root.Flags.SetOutput(&bytes.Buffer{})
+		err := root.Execute(v.args)
+		if err == nil || err.Error() != v.want {
+			t.Errorf("%q: got %v; want %s", v.args, err, v.want)
+		}
+		if ran != "" {
+			t.Errorf("%q: ran %q", v.args, ran)
+		}
+	}
+}
+
+func TestCommandErrorHandling(t *testing.T) {
+	root := NewCommand("prog", "a test program", nil)
+	root.Flags = NewFlagSet("prog", PanicOnError, false)
+	var buf bytes.Buffer
+	root.Flags.SetOutput(&buf)
+	root.AddCommand(NewCommand("status", "show status", func(*Command, []string) error { return nil }))
+
+	defer func() {
+		if _, ok := recover().(*CommandError); !ok {
+			t.Error("an unknown command did not panic with a *CommandError")
+		}
+		if want := "unknown command \"bogus\" for \"prog\"; valid commands: status\n"; buf.String() != want {
+			t.Errorf("output %q; want %q", buf.String(), want)
+		}
+	}()
+	root.Execute([]string{"bogus"})
+}
+
+func TestCommandUsage(t *testing.T) {
+	var ran string
+	var rest []string
+	root, _, _ := newTestTree(&ran, &rest)
+	var buf bytes.Buffer
+	root.Flags.SetOutput(&buf)
+	root.Usage()
+	want := "\nUsage: prog [flags] <command>\n" +
+		"\na test program\n" +
+		"\nCommands:\n" +
+		"  remote  manage remotes\n" +
+		"  status  show status\n" +
+		"\nUse \"prog help <command>\" for more information about a command.\n" +
+		"\nFlags:\n" +
+		"  -v, --verbose\n    \tverbose output\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
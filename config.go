@@ -0,0 +1,147 @@
+package flags
+
+import "fmt"
+
+// UnknownKeyPolicy selects how configuration loaders such as LoadJSON treat
+// keys that do not name a defined flag.
+type UnknownKeyPolicy int
+
+// These constants select the behavior of configuration loaders for unknown keys.
+const (
+	UnknownKeyError  UnknownKeyPolicy = iota // Return a *ConfigError.
+	UnknownKeyWarn                           // Print a warning to the output of the flag set.
+	UnknownKeyIgnore                         // Skip the key silently.
+)
+
+// A ConfigError reports a problem with a configuration source.
+type ConfigError struct {
+	Source string // file name, or empty for readers
+	Path   string // location in the source, such as a JSON path or a line
+	Flag   string // name of the flag concerned, if known
+	Err    error
+}
+
+func (e *ConfigError) Error() string {
+	s := ""
+	if e.Source != "" {
+		s += e.Source + ": "
+	}
+	if e.Path != "" {
+		s += e.Path + ": "
+	}
+	if e.Flag != "" {
+		s += "flag --" + e.Flag + ": "
+	}
+	return s + e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// configArg is an argument for Value.Set read from a configuration source.
+type configArg struct {
+	value  string
+	source string
+	path   string
+}
+
+// location returns the source and path of the argument for Flag.Location.
+func (a configArg) location() string {
+	if a.source == "" {
+		return a.path
+	}
+	if a.path == "" {
+		return a.source
+	}
+	return a.source + ": " + a.path
+}
+
+// configLoader collects the values read from one configuration source.
+type configLoader struct {
+	f      *FlagSet
+	source string
+	values map[string][]configArg
+}
+
+func (f *FlagSet) newConfigLoader(source string) *configLoader {
+	return &configLoader{f: f, source: source, values: make(map[string][]configArg)}
+}
+
+// lookup returns the flag with the given name. For an unknown name it
+// returns nil and an error if the flag set does not ignore unknown keys.
+func (l *configLoader) lookup(name, path string) (*Flag, error) {
+	if flag, ok := l.f.formal[name]; ok {
+		return flag, nil
+	}
+	err := &ConfigError{Source: l.source, Path: path, Err: fmt.Errorf("unknown flag %q", name)}
+	switch l.f.UnknownKeys {
+	case UnknownKeyError:
+		return nil, err
+	case UnknownKeyWarn:
+		fmt.Fprintf(l.f.Output(), "warning: %v\n", err)
+	}
+	return nil, nil
+}
+
+// add records a value for flag found at path in the source. A flag
+// added more than once receives every value in order, as if it had
+// been repeated on the command line.
+func (l *configLoader) add(flag *Flag, path, value string) {
+	l.values[flag.Name] = append(l.values[flag.Name], configArg{value: value, source: l.source, path: path})
+}
+
+// commit hands the collected values to the flag set. Values replace those
+// of an earlier source for the same flag. Parse applies them with
+// applyConfig; if the flag set has already been parsed they are applied
+// to the flags still unset now.
+func (l *configLoader) commit() error {
+	if l.f.parsed {
+		return l.f.applyConfig(l.values)
+	}
+	if l.f.config == nil {
+		l.f.config = make(map[string][]configArg)
+	}
+	for name, args := range l.values {
+		l.f.config[name] = args
+	}
+	return nil
+}
+
+// applyConfig sets each flag not yet set from the configuration values.
+// Parse calls it last, so that configuration ranks below the command
+// line, the environment and the sources added with AddSource. A flag with
+// several values receives them all before its callback is called once.
+func (f *FlagSet) applyConfig(config map[string][]configArg) error {
+	for _, flag := range sortFlags(f.formal) {
+		args, ok := config[flag.Name]
+		if !ok {
+			continue
+		}
+		if _, ok := f.actual[flag.Name]; ok {
+			continue
+		}
+		for _, arg := range args {
+			if err := flag.Value.Set(arg.value); err != nil {
+				err = fmt.Errorf("invalid value %q: %v", arg.value, err)
+				return f.fail(&ConfigError{Source: arg.source, Path: arg.path, Flag: flag.Name, Err: err})
+			}
+			flag.record(OriginConfig, arg.value, arg.location())
+		}
+		if err := f.callback(flag); err != nil {
+			return err
+		}
+		if f.actual == nil {
+			f.actual = make(map[string]*Flag)
+		}
+		f.actual[flag.Name] = flag
+	}
+	return nil
+}
+
+// literal returns the argument for which v.Set adds elem as a single
+// element of a slice-valued flag, or elem itself for other flags.
+func literal(v Value, elem string) string {
+	if s, ok := v.(interface{ literal(string) string }); ok {
+		return s.literal(elem)
+	}
+	return elem
+}
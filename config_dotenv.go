@@ -0,0 +1,394 @@
+package flags
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// LoadDotenv reads a .env file from r and uses it to set the flags of the
+// flag set. Each KEY=value line sets the flag whose environment variable
+// is KEY: one named by the EnvVars option of the flag, or the upper-snake
+// form of the flag name after EnvPrefix, so that the key LISTEN_ADDR, or
+// MYAPP_LISTEN_ADDR with EnvPrefix "MYAPP_", sets the flag "listen-addr".
+// Keys that set no flag are skipped, since a .env file is usually shared
+// with other programs; only when EnvPrefix is set are the unknown keys
+// with the prefix handled as selected by UnknownKeys.
+//
+// Lines starting with '#' are comments, as is the rest of a line after
+// " #" in an unquoted value. A line may start with "export". Values may be
+// single-quoted verbatim or double-quoted with the escapes \n, \r, \t, \",
+// \\ and \$, and quoted values may span lines. In unquoted and
+// double-quoted values, ${VAR} and $VAR are replaced with the value of VAR
+// given earlier in the file or else in the environment, and ${VAR:-word}
+// with word if VAR is unset or empty.
+//
+// The values rank below the other layers described at Parse. Errors are
+// of type *ConfigError and include the line number.
+func (f *FlagSet) LoadDotenv(r io.Reader) error {
+	return f.loadDotenv(r, "")
+}
+
+// LoadDotenvFile is like LoadDotenv but reads the named file.
+func (f *FlagSet) LoadDotenvFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return &ConfigError{Source: path, Err: err}
+	}
+	defer file.Close()
+	return f.loadDotenv(file, path)
+}
+
+// LoadDotenv reads a .env file from r and uses it to set the command-line
+// flags. See FlagSet.LoadDotenv.
+func LoadDotenv(r io.Reader) error {
+	return CommandLine.LoadDotenv(r)
+}
+
+// LoadDotenvFile reads the named .env file and uses it to set the
+// command-line flags. See FlagSet.LoadDotenv.
+func LoadDotenvFile(path string) error {
+	return CommandLine.LoadDotenvFile(path)
+}
+
+// DotenvFileVar defines a string flag with specified name, default value,
+// and usage string, whose value names a .env file. After the command line
+// and the environment, Parse loads the file with LoadDotenvFile, so that
+// its values rank below the environment and any sources added with
+// AddSource, and above other configuration files. A missing file is an
+// error only if the flag was set. A flag set may have only one such flag;
+// defining a second one panics.
+// The argument p points to a string variable in which to store the value of the flag.
+func (f *FlagSet) DotenvFileVar(p *string, name string, alias rune, value string, usage string, fn Callback, opts ...Option) {
+	if f.dotenv != nil {
+		var msg string
+		if f.name == "" {
+			msg = fmt.Sprintf("dotenv file flag redefined: %s as %s", f.dotenv.Name, name)
+		} else {
+			msg = fmt.Sprintf("%s dotenv file flag redefined: %s as %s", f.name, f.dotenv.Name, name)
+		}
+		fmt.Fprintln(f.Output(), msg)
+		panic(msg) // Happens only if DotenvFile is called twice
+	}
+	f.StringVar(p, name, alias, value, usage, fn, opts...)
+	f.dotenv = f.formal[name]
+}
+
+// DotenvFileVar defines a string flag with specified name, default value,
+// and usage string, whose value names a .env file loaded by Parse.
+// See FlagSet.DotenvFileVar.
+func DotenvFileVar(p *string, name string, alias rune, value string, usage string, fn Callback, opts ...Option) {
+	CommandLine.DotenvFileVar(p, name, alias, value, usage, fn, opts...)
+}
+
+// DotenvFile defines a string flag with specified name, default value, and
+// usage string, whose value names a .env file loaded by Parse.
+// See FlagSet.DotenvFileVar.
+// The return value is the address of a string variable that stores the value of the flag.
+func (f *FlagSet) DotenvFile(name string, alias rune, value string, usage string, fn Callback, opts ...Option) *string {
+	p := new(string)
+	f.DotenvFileVar(p, name, alias, value, usage, fn, opts...)
+	return p
+}
+
+// DotenvFile defines a string flag with specified name, default value, and
+// usage string, whose value names a .env file loaded by Parse.
+// See FlagSet.DotenvFileVar.
+// The return value is the address of a string variable that stores the value of the flag.
+func DotenvFile(name string, alias rune, value string, usage string, fn Callback, opts ...Option) *string {
+	return CommandLine.DotenvFile(name, alias, value, usage, fn, opts...)
+}
+
+// loadDotenvFlag loads the file named by the flag defined by DotenvFile.
+func (f *FlagSet) loadDotenvFlag() error {
+	if f.dotenv == nil {
+		return nil
+	}
+	path := f.dotenv.Value.String()
+	if path == "" {
+		return nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if _, set := f.actual[f.dotenv.Name]; !set && os.IsNotExist(err) {
+			return nil
+		}
+		return f.fail(&ConfigError{Source: path, Err: err})
+	}
+	defer file.Close()
+	l, err := f.readDotenv(file, path)
+	if err != nil {
+		return f.fail(err)
+	}
+	return l.commit()
+}
+
+func (f *FlagSet) loadDotenv(r io.Reader, source string) error {
+	l, err := f.readDotenv(r, source)
+	if err != nil {
+		return err
+	}
+	return l.commit()
+}
+
+// readDotenv reads a .env file from r into a loader for the flag set.
+func (f *FlagSet) readDotenv(r io.Reader, source string) (*configLoader, error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, &ConfigError{Source: source, Err: err}
+	}
+
+	keys := make(map[string]*Flag)
+	for _, flag := range f.formal {
+		keys[envName(f.EnvPrefix, flag.Name)] = flag
+	}
+	for _, flag := range f.formal {
+		for _, key := range flag.EnvVars {
+			keys[key] = flag
+		}
+	}
+
+	p := &dotenvParser{src: string(src), line: 1, vars: make(map[string]string)}
+	l := f.newConfigLoader(source)
+	for {
+		key, value, line, err := p.next()
+		if err != nil {
+			return nil, &ConfigError{Source: source, Path: fmt.Sprintf("line %d", p.line), Err: err}
+		}
+		if key == "" {
+			break
+		}
+		p.vars[key] = value
+
+		path := fmt.Sprintf("line %d", line)
+		flag, ok := keys[key]
+		if !ok {
+			if f.EnvPrefix == "" || !strings.HasPrefix(key, f.EnvPrefix) {
+				continue
+			}
+			if flag, err = l.lookup(key, path); flag == nil {
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		l.add(flag, path, value)
+	}
+	return l, nil
+}
+
+type dotenvParser struct {
+	src  string
+	pos  int
+	line int
+	vars map[string]string // variables defined so far
+}
+
+func (p *dotenvParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *dotenvParser) skipSpaces() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.pos++
+	}
+}
+
+// skipLine skips the rest of the line, which may only hold a comment.
+func (p *dotenvParser) skipLine() error {
+	p.skipSpaces()
+	switch p.peek() {
+	case '#':
+		for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+			p.pos++
+		}
+	case 0, '\n', '\r':
+	default:
+		return fmt.Errorf("unexpected %q after value", p.src[p.pos])
+	}
+	if p.peek() == '\r' {
+		p.pos++
+	}
+	if p.peek() == '\n' {
+		p.pos++
+		p.line++
+	}
+	return nil
+}
+
+// next returns the next assignment and the line it starts on, or an empty
+// key at the end of the input.
+func (p *dotenvParser) next() (key, value string, line int, err error) {
+	for {
+		p.skipSpaces()
+		if c := p.peek(); c != '#' && c != '\n' && c != '\r' {
+			break
+		}
+		if p.pos >= len(p.src) {
+			return "", "", 0, nil
+		}
+		if err := p.skipLine(); err != nil {
+			return "", "", 0, err
+		}
+	}
+	if p.pos >= len(p.src) {
+		return "", "", 0, nil
+	}
+
+	line = p.line
+	key = p.name()
+	if key == "export" && (p.peek() == ' ' || p.peek() == '\t') {
+		p.skipSpaces()
+		key = p.name()
+	}
+	if key == "" {
+		return "", "", 0, fmt.Errorf("expected KEY=value")
+	}
+	p.skipSpaces()
+	if p.peek() != '=' {
+		return "", "", 0, fmt.Errorf("expected '=' after %s", key)
+	}
+	p.pos++
+	p.skipSpaces()
+
+	switch p.peek() {
+	case '\'':
+		end := strings.IndexByte(p.src[p.pos+1:], '\'')
+		if end < 0 {
+			return "", "", 0, fmt.Errorf("unterminated single-quoted value")
+		}
+		value = p.src[p.pos+1 : p.pos+1+end]
+		p.line += strings.Count(value, "\n")
+		p.pos += end + 2
+	case '"':
+		if value, err = p.doubleQuoted(); err != nil {
+			return "", "", 0, err
+		}
+	default:
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] != '\n' && p.src[p.pos] != '\r' &&
+			!(p.src[p.pos] == '#' && (p.pos == start || p.src[p.pos-1] == ' ' || p.src[p.pos-1] == '\t')) {
+			p.pos++
+		}
+		value = p.expand(strings.TrimRight(p.src[start:p.pos], " \t"))
+	}
+	return key, value, line, p.skipLine()
+}
+
+// name scans a variable name.
+func (p *dotenvParser) name() string {
+	start := p.pos
+	for p.pos < len(p.src) && isDotenvNameByte(p.src[p.pos], p.pos == start) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func isDotenvNameByte(c byte, first bool) bool {
+	switch {
+	case c == '_' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z':
+		return true
+	case '0' <= c && c <= '9' || c == '.' || c == '-':
+		return !first
+	}
+	return false
+}
+
+// doubleQuoted scans a double-quoted value with escapes and expansions.
+func (p *dotenvParser) doubleQuoted() (string, error) {
+	var b strings.Builder
+	for p.pos++; p.pos < len(p.src); p.pos++ {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			p.pos++
+			switch p.peek() {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(p.src[p.pos])
+			default:
+				b.WriteByte('\\')
+				p.pos--
+			}
+		case '$':
+			s, n := p.variable(p.src[p.pos:])
+			b.WriteString(s)
+			p.pos += n - 1
+		default:
+			if c == '\n' {
+				p.line++
+			}
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated double-quoted value")
+}
+
+// expand replaces the variables in s.
+func (p *dotenvParser) expand(s string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' {
+			b.WriteByte(s[i])
+			continue
+		}
+		v, n := p.variable(s[i:])
+		b.WriteString(v)
+		i += n - 1
+	}
+	return b.String()
+}
+
+// variable returns the value of the variable reference at the start of s,
+// which starts with '$', and its length. A '$' starting no reference
+// stands for itself.
+func (p *dotenvParser) variable(s string) (string, int) {
+	if strings.HasPrefix(s, "${") {
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return "$", 1
+		}
+		name, word := s[2:end], ""
+		hasWord := false
+		if i := strings.Index(name, ":-"); i >= 0 {
+			name, word, hasWord = name[:i], name[i+2:], true
+		}
+		v := p.lookup(name)
+		if v == "" && hasWord {
+			v = word
+		}
+		return v, end + 1
+	}
+	n := 1
+	for n < len(s) && isDotenvNameByte(s[n], n == 1) && s[n] != '.' && s[n] != '-' {
+		n++
+	}
+	if n == 1 {
+		return "$", 1
+	}
+	return p.lookup(s[1:n]), n
+}
+
+func (p *dotenvParser) lookup(name string) string {
+	if v, ok := p.vars[name]; ok {
+		return v
+	}
+	return os.Getenv(name)
+}
@@ -0,0 +1,173 @@
+package flags_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+const dotenvConfig = `# local development
+export MYAPP_HOST=file.example.com
+MYAPP_PORT = 8080 # inline comment
+MYAPP_NAME="${MYAPP_HOST}:$MYAPP_PORT\t\"quoted\""
+MYAPP_MOTD='single $NOT_EXPANDED
+second line'
+MYAPP_TAGS=a
+MYAPP_HOME=${DOTENV_TEST_HOME:-/default}/app
+MYAPP_USER=${DOTENV_TEST_USER}
+DATABASE_URL=postgres://elsewhere
+`
+
+func TestLoadDotenv(t *testing.T) {
+	setenv(t, "DOTENV_TEST_USER", "gopher")
+	fs := NewFlagSet("dotenv test", ContinueOnError, false)
+	fs.EnvPrefix = "MYAPP_"
+	host := fs.String("host", 0, "localhost", "", nil)
+	port := fs.Int("port", 0, 80, "", nil)
+	name := fs.String("name", 0, "", "", nil)
+	motd := fs.String("motd", 0, "", "", nil)
+	home := fs.String("home", 0, "", "", nil)
+	user := fs.String("user", 0, "", "", nil)
+	var calls []string
+	tags := fs.StringSlice("tags", 0, []string{"x"}, "", func(g Getter) error {
+		calls = append(calls, g.String())
+		return nil
+	})
+
+	if err := fs.LoadDotenv(strings.NewReader(dotenvConfig)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"--port", "9090"}); err != nil {
+		t.Fatal(err)
+	}
+	if *host != "file.example.com" || *port != 9090 || *home != "/default/app" || *user != "gopher" {
+		t.Errorf("got host=%q port=%d home=%q user=%q", *host, *port, *home, *user)
+	}
+	if want := "file.example.com:8080\t\"quoted\""; *name != want {
+		t.Errorf("name = %q; want %q", *name, want)
+	}
+	if want := "single $NOT_EXPANDED\nsecond line"; *motd != want {
+		t.Errorf("motd = %q; want %q", *motd, want)
+	}
+	if !reflect.DeepEqual(*tags, []string{"a"}) || !reflect.DeepEqual(calls, []string{"[a]"}) {
+		t.Errorf("tags = %q, callback calls %q", *tags, calls)
+	}
+}
+
+func TestDotenvFileFlag(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "local.env")
+	if err := ioutil.WriteFile(path, []byte("LISTEN_ADDR=:8080\nVERBOSE=true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []struct {
+		args    []string
+		env     string
+		addr    string
+		verbose bool
+		err     bool
+	}{
+		{args: nil, addr: ":80"},
+		{args: []string{"--env-file", path}, addr: ":8080", verbose: true},
+		{args: []string{"--env-file", path, "--listen-addr=:9090"}, addr: ":9090", verbose: true},
+		{args: []string{"--env-file", path}, env: ":7070", addr: ":7070", verbose: true},
+		{args: []string{"--env-file", filepath.Join(dir, "missing.env")}, err: true},
+	}
+
+	for _, v := range data {
+		fs := NewFlagSet("dotenv test", ContinueOnError, false)
+		fs.SetOutput(&bytes.Buffer{})
+		fs.DotenvFile("env-file", 0, filepath.Join(dir, "default.env"), "", nil)
+		addr := fs.String("listen-addr", 0, ":80", "", nil, EnvVars("DOTENV_TEST_ADDR"))
+		verbose := fs.Bool("verbose", 0, false, "", nil)
+		if v.env != "" {
+			setenv(t, "DOTENV_TEST_ADDR", v.env)
+		} else {
+			os.Unsetenv("DOTENV_TEST_ADDR")
+		}
+
+		err := fs.Parse(v.args)
+		if v.err {
+			if _, ok := err.(*ConfigError); !ok {
+				t.Errorf("%q: got %v; want *ConfigError", v.args, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", v.args, err)
+			continue
+		}
+		if *addr != v.addr || *verbose != v.verbose {
+			t.Errorf("%q: got addr=%q verbose=%t; want %q %t", v.args, *addr, *verbose, v.addr, v.verbose)
+		}
+	}
+}
+
+func TestLoadDotenvUnknownKeys(t *testing.T) {
+	fs := NewFlagSet("dotenv test", ContinueOnError, false)
+	port := fs.Int("port", 0, 0, "", nil)
+	if err := fs.LoadDotenv(strings.NewReader("DATABASE_URL=postgres://x\nPORT=8080\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse(nil); err != nil || *port != 8080 {
+		t.Errorf("got %v, port=%d; want nil, 8080", err, *port)
+	}
+
+	fs = NewFlagSet("dotenv test", ContinueOnError, false)
+	fs.EnvPrefix = "APP_"
+	fs.Int("port", 0, 0, "", nil)
+	err := fs.LoadDotenv(strings.NewReader("DATABASE_URL=postgres://x\nAPP_PROT=8080\n"))
+	if ce, ok := err.(*ConfigError); !ok || ce.Path != "line 2" {
+		t.Errorf("got %v; want an error for APP_PROT at line 2", err)
+	}
+}
+
+func TestDotenvFileRedefined(t *testing.T) {
+	fs := NewFlagSet("dotenv test", ContinueOnError, false)
+	fs.SetOutput(&bytes.Buffer{})
+	fs.DotenvFile("env-file", 0, ".env", "", nil)
+	defer func() {
+		if recover() == nil {
+			t.Error("a second DotenvFile did not panic")
+		}
+	}()
+	fs.DotenvFile("env-local", 0, ".env.local", "", nil)
+}
+
+func TestLoadDotenvErrors(t *testing.T) {
+	data := []struct {
+		config string
+		path   string
+		flag   string
+	}{
+		{config: "PORT=x\n", path: "line 1", flag: "port"},
+		{config: "\n# comment\nPORT\n", path: "line 3"},
+		{config: "NAME='open\n\n", path: "line 1"},
+		{config: "NAME=\"a\nb\" trailing\n", path: "line 2"},
+	}
+
+	for _, v := range data {
+		fs := NewFlagSet("dotenv test", ContinueOnError, false)
+		fs.Int("port", 0, 0, "", nil)
+		fs.String("name", 0, "", "", nil)
+
+		err := fs.LoadDotenv(strings.NewReader(v.config))
+		if err == nil {
+			err = fs.Parse(nil)
+		}
+		if ce, ok := err.(*ConfigError); !ok || ce.Path != v.path || ce.Flag != v.flag {
+			t.Errorf("%q: got %#v; want path %q flag %q", v.config, err, v.path, v.flag)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package flags
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Section makes LoadINI use the keys of the named section to set the
+// flags of child, instead of the flags of f whose names are the keys
+// prefixed with the section name and a dot.
+func (f *FlagSet) Section(name string, child *FlagSet) {
+	if f.sections == nil {
+		f.sections = make(map[string]*FlagSet)
+	}
+	f.sections[name] = child
+}
+
+// LoadINI reads an INI file from r and uses it to set the flags of the flag
+// set. Keys before the first section header name flags by their long name.
+// The keys of a [section] name the flags of the child flag set registered
+// with Section, or else the flags whose names are the keys prefixed with
+// the section name and a dot, so that "port" in [server] sets the flag
+// "server.port". A key given more than once calls Set once per value, as
+// if the flag had been repeated on the command line.
+//
+// Lines starting with ';' or '#' are comments, as is the rest of a line
+// after " ;" or " #" in an unquoted value. Values may be double-quoted with
+// Go escape sequences or single-quoted verbatim. A line ending with a
+// backslash continues on the next line.
+//
+// The values rank below the other layers described at Parse. Keys naming
+// no flag are handled as selected by UnknownKeys. Errors are of type
+// *ConfigError and include the line number.
+func (f *FlagSet) LoadINI(r io.Reader) error {
+	return f.loadINI(r, "")
+}
+
+// LoadINIFile is like LoadINI but reads the named file.
+func (f *FlagSet) LoadINIFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return &ConfigError{Source: path, Err: err}
+	}
+	defer file.Close()
+	return f.loadINI(file, path)
+}
+
+// LoadINI reads an INI file from r and uses it to set the command-line
+// flags. See FlagSet.LoadINI.
+func LoadINI(r io.Reader) error {
+	return CommandLine.LoadINI(r)
+}
+
+// LoadINIFile reads the named INI file and uses it to set the command-line
+// flags. See FlagSet.LoadINI.
+func LoadINIFile(path string) error {
+	return CommandLine.LoadINIFile(path)
+}
+
+func (f *FlagSet) loadINI(r io.Reader, source string) error {
+	l := f.newConfigLoader(source)
+	loaders := []*configLoader{l}
+	children := make(map[*FlagSet]*configLoader)
+	target, prefix := l, ""
+
+	sc := bufio.NewScanner(r)
+	for lineno := 1; sc.Scan(); lineno++ {
+		path := fmt.Sprintf("line %d", lineno)
+		line := sc.Text()
+		for strings.HasSuffix(line, "\\") && sc.Scan() {
+			lineno++
+			line = line[:len(line)-1] + strings.TrimLeft(sc.Text(), " \t")
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return &ConfigError{Source: source, Path: path, Err: fmt.Errorf("bad section header %s", line)}
+			}
+			section := strings.TrimSpace(line[1:end])
+			rest := strings.TrimSpace(line[end+1:])
+			if section == "" || rest != "" && rest[0] != ';' && rest[0] != '#' {
+				return &ConfigError{Source: source, Path: path, Err: fmt.Errorf("bad section header %s", line)}
+			}
+			target, prefix = l, section+"."
+			if child, ok := f.sections[section]; ok {
+				if children[child] == nil {
+					children[child] = child.newConfigLoader(source)
+					loaders = append(loaders, children[child])
+				}
+				target, prefix = children[child], ""
+			}
+			continue
+		}
+
+		i := strings.IndexByte(line, '=')
+		if i <= 0 {
+			return &ConfigError{Source: source, Path: path, Err: fmt.Errorf("expected key = value")}
+		}
+		name := prefix + strings.TrimSpace(line[:i])
+		flag, err := target.lookup(name, path)
+		if flag == nil {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		value, err := iniValue(strings.TrimSpace(line[i+1:]))
+		if err != nil {
+			return &ConfigError{Source: source, Path: path, Flag: flag.Name, Err: err}
+		}
+		target.add(flag, path, value)
+	}
+	if err := sc.Err(); err != nil {
+		return &ConfigError{Source: source, Err: err}
+	}
+
+	for _, l := range loaders {
+		if err := l.commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iniValue returns the value of the text after '=', removing quotes and
+// trailing comments.
+func iniValue(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	var value, rest string
+	switch s[0] {
+	case '"':
+		i := 1
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' {
+				i++
+			}
+		}
+		if i >= len(s) {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		v, err := strconv.Unquote(s[:i+1])
+		if err != nil {
+			return "", fmt.Errorf("bad quoted value %s", s[:i+1])
+		}
+		value, rest = v, s[i+1:]
+	case '\'':
+		i := strings.IndexByte(s[1:], '\'')
+		if i < 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		value, rest = s[1:i+1], s[i+2:]
+	default:
+		for i := 1; i < len(s); i++ {
+			if (s[i] == ';' || s[i] == '#') && (s[i-1] == ' ' || s[i-1] == '\t') {
+				return strings.TrimSpace(s[:i]), nil
+			}
+		}
+		return s, nil
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest != "" && rest[0] != ';' && rest[0] != '#' {
+		return "", fmt.Errorf("unexpected text after quoted value: %s", rest)
+	}
+	return value, nil
+}
@@ -0,0 +1,95 @@
+package flags_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+const iniConfig = `; global settings
+port = 8080
+host = "ini.example.com" ; quoted
+motd = 'say "hi"'
+tag = a
+tag = b,c
+banner = first \
+         second
+
+[server]
+read-timeout = 5s # comment
+
+[db] ; database
+user = admin
+`
+
+func TestLoadINI(t *testing.T) {
+	fs := NewFlagSet("ini test", ContinueOnError, false)
+	port := fs.Int("port", 0, 80, "", nil)
+	host := fs.String("host", 0, "", "", nil)
+	motd := fs.String("motd", 0, "", "", nil)
+	banner := fs.String("banner", 0, "", "", nil)
+	calls := 0
+	tags := fs.StringSlice("tag", 0, nil, "", func(g Getter) error {
+		calls++
+		return nil
+	})
+	timeout := fs.Duration("server.read-timeout", 0, 0, "", nil)
+
+	db := NewFlagSet("db", ContinueOnError, false)
+	user := db.String("user", 0, "", "", nil)
+	fs.Section("db", db)
+
+	if err := fs.LoadINI(strings.NewReader(iniConfig)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"--port=9090"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 9090 {
+		t.Errorf("port = %d; command line should win over the file", *port)
+	}
+	if *host != "ini.example.com" || *motd != `say "hi"` || *banner != "first second" {
+		t.Errorf("got host=%q motd=%q banner=%q", *host, *motd, *banner)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(*tags, want) || calls != 1 {
+		t.Errorf("tags = %q after %d callbacks; want %q after 1", *tags, calls, want)
+	}
+	if timeout.String() != "5s" {
+		t.Errorf("server.read-timeout = %v; want 5s", *timeout)
+	}
+	if *user != "admin" {
+		t.Errorf("db user = %q; want admin", *user)
+	}
+}
+
+func TestLoadINIErrors(t *testing.T) {
+	data := []struct {
+		config string
+		path   string
+	}{
+		{config: "port = 1\n[server\n", path: "line 2"},
+		{config: "[server] port = 1\n", path: "line 1"},
+		{config: "[ ] ; empty\n", path: "line 1"},
+		{config: "port\n", path: "line 1"},
+		{config: "\n\nport = \"80\n", path: "line 3"},
+		{config: "[server]\nname = x\n", path: "line 2"},
+		{config: "port = x\n", path: "line 1"},
+	}
+
+	for _, v := range data {
+		fs := NewFlagSet("ini test", ContinueOnError, false)
+		fs.Int("port", 0, 0, "", nil)
+		err := fs.LoadINI(strings.NewReader(v.config))
+		if err == nil {
+			err = fs.Parse(nil)
+		}
+		if ce, ok := err.(*ConfigError); !ok || ce.Path != v.path {
+			t.Errorf("%q: got %v; want error at %s", v.config, err, v.path)
+		}
+	}
+}
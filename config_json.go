@@ -0,0 +1,152 @@
+package flags
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// LoadJSON reads a JSON object from r and uses it to set the flags of the
+// flag set. Each key names a flag by its long name, and the keys of nested
+// objects are joined with dots, so that {"server": {"port": 80}} sets the
+// flag "server.port". An object given for a map-valued flag sets its
+// entries instead. An array calls Set once per element, as if the flag had
+// been repeated on the command line. Null values are ignored.
+//
+// The values rank below the other layers described at Parse. Keys naming
+// no flag are handled as selected by UnknownKeys. Errors are of type
+// *ConfigError and include the JSON path of the value.
+func (f *FlagSet) LoadJSON(r io.Reader) error {
+	return f.loadJSON(r, "")
+}
+
+// LoadJSONFile is like LoadJSON but reads the named file.
+func (f *FlagSet) LoadJSONFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return &ConfigError{Source: path, Err: err}
+	}
+	defer file.Close()
+	return f.loadJSON(file, path)
+}
+
+// LoadJSON reads a JSON object from r and uses it to set the command-line
+// flags. See FlagSet.LoadJSON.
+func LoadJSON(r io.Reader) error {
+	return CommandLine.LoadJSON(r)
+}
+
+// LoadJSONFile reads a JSON object from the named file and uses it to set
+// the command-line flags. See FlagSet.LoadJSON.
+func LoadJSONFile(path string) error {
+	return CommandLine.LoadJSONFile(path)
+}
+
+func (f *FlagSet) loadJSON(r io.Reader, source string) error {
+	var v interface{}
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return &ConfigError{Source: source, Err: err}
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return &ConfigError{Source: source, Path: "$", Err: fmt.Errorf("expected an object")}
+	}
+	l := f.newConfigLoader(source)
+	if err := l.walkJSON("", "$", obj); err != nil {
+		return err
+	}
+	return l.commit()
+}
+
+func (l *configLoader) walkJSON(prefix, path string, obj map[string]interface{}) error {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name, p := prefix+key, path+"."+key
+		switch v := obj[key].(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			if m, ok := l.f.formal[name]; ok {
+				if mv, ok := m.Value.(*mapValue); ok {
+					if err := l.addJSONMap(m, mv, p, v); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			if err := l.walkJSON(name+".", p, v); err != nil {
+				return err
+			}
+		case []interface{}:
+			flag, err := l.lookup(name, p)
+			if flag == nil {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			for i, e := range v {
+				ep := fmt.Sprintf("%s[%d]", p, i)
+				s, err := jsonScalar(e)
+				if err != nil {
+					return &ConfigError{Source: l.source, Path: ep, Flag: name, Err: err}
+				}
+				l.add(flag, ep, literal(flag.Value, s))
+			}
+		default:
+			flag, err := l.lookup(name, p)
+			if flag == nil {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			s, err := jsonScalar(v)
+			if err != nil {
+				return &ConfigError{Source: l.source, Path: p, Flag: name, Err: err}
+			}
+			l.add(flag, p, s)
+		}
+	}
+	return nil
+}
+
+func (l *configLoader) addJSONMap(flag *Flag, mv *mapValue, path string, obj map[string]interface{}) error {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		p := path + "." + key
+		s, err := jsonScalar(obj[key])
+		if err != nil {
+			return &ConfigError{Source: l.source, Path: p, Flag: flag.Name, Err: err}
+		}
+		l.add(flag, p, mv.pair(key, s))
+	}
+	return nil
+}
+
+// jsonScalar returns the text of a JSON string, number or boolean.
+func jsonScalar(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return "", fmt.Errorf("expected a string, number or boolean")
+}
@@ -0,0 +1,122 @@
+package flags_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+const jsonConfig = `{
+	"port": 8080,
+	"host": "file.example.com",
+	"verbose": true,
+	"server": {"read-timeout": "5s", "tls": {"cert": "cert.pem"}},
+	"tags": ["a,b", "c"],
+	"labels": {"env": "prod", "team": "core"},
+	"unused": null
+}`
+
+func TestLoadJSON(t *testing.T) {
+	fs := NewFlagSet("json test", ContinueOnError, false)
+	port := fs.Int("port", 'p', 80, "", nil)
+	host := fs.String("host", 0, "localhost", "", nil)
+	verbose := fs.Bool("verbose", 0, false, "", nil)
+	timeout := fs.Duration("server.read-timeout", 0, 0, "", nil)
+	cert := fs.String("server.tls.cert", 0, "", "", nil, Required())
+	var seen [][]string
+	tags := fs.StringSlice("tags", 0, nil, "", func(g Getter) error {
+		seen = append(seen, g.Get().([]string))
+		return nil
+	})
+	labels := fs.StringToString("labels", 0, nil, "", nil)
+	fs.String("unused", 0, "", "", nil)
+
+	if err := fs.LoadJSON(strings.NewReader(jsonConfig)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"--host", "cli.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 8080 || !*verbose || timeout.String() != "5s" || *cert != "cert.pem" {
+		t.Errorf("got port=%d verbose=%t timeout=%v cert=%q", *port, *verbose, *timeout, *cert)
+	}
+	if *host != "cli.example.com" {
+		t.Errorf("host = %q; command line should win over the file", *host)
+	}
+	if want := []string{"a,b", "c"}; !reflect.DeepEqual(*tags, want) || !reflect.DeepEqual(seen, [][]string{want}) {
+		t.Errorf("tags = %q, callbacks saw %q; want %q once", *tags, seen, want)
+	}
+	if want := map[string]string{"env": "prod", "team": "core"}; !reflect.DeepEqual(*labels, want) {
+		t.Errorf("labels = %v; want %v", *labels, want)
+	}
+}
+
+func TestLoadJSONFileAfterParse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(`{"port": 8080, "host": "file"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := NewFlagSet("json test", ContinueOnError, false)
+	port := fs.Int("port", 0, 80, "", nil)
+	host := fs.String("host", 0, "localhost", "", nil)
+	if err := fs.Parse([]string{"--host=cli"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.LoadJSONFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 8080 || *host != "cli" {
+		t.Errorf("got port=%d host=%q; want 8080 cli", *port, *host)
+	}
+}
+
+func TestLoadJSONErrors(t *testing.T) {
+	data := []struct {
+		config  string
+		unknown UnknownKeyPolicy
+		path    string
+		flag    string
+		warning bool
+	}{
+		{config: `{"server": {"port": "x"}}`, path: "$.server.port", flag: "server.port"},
+		{config: `{"server": {"port": [1, {}]}}`, path: "$.server.port[1]", flag: "server.port"},
+		{config: `{"server": {"name": 1}}`, path: "$.server.name"},
+		{config: `{"server": {"name": 1}}`, unknown: UnknownKeyWarn, warning: true},
+		{config: `{"server": {"name": 1}}`, unknown: UnknownKeyIgnore},
+	}
+
+	for _, v := range data {
+		fs := NewFlagSet("json test", ContinueOnError, false)
+		var buf bytes.Buffer
+		fs.SetOutput(&buf)
+		fs.UnknownKeys = v.unknown
+		fs.Int("server.port", 0, 0, "", nil)
+
+		err := fs.LoadJSON(strings.NewReader(v.config))
+		if err == nil {
+			err = fs.Parse(nil)
+		}
+		if v.path == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", v.config, err)
+			}
+		} else if ce, ok := err.(*ConfigError); !ok || ce.Path != v.path || ce.Flag != v.flag {
+			t.Errorf("%s: got %#v; want path %q flag %q", v.config, err, v.path, v.flag)
+		}
+		if got := strings.Contains(buf.String(), "warning"); got != v.warning {
+			t.Errorf("%s: warning printed = %t; want %t", v.config, got, v.warning)
+		}
+	}
+}
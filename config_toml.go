@@ -0,0 +1,601 @@
+package flags
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// LoadTOML reads a TOML document from r and uses it to set the flags of the
+// flag set. Keys name flags by their long name, and the keys of tables,
+// dotted keys and inline tables are joined with dots, so that "port" in
+// [server] sets the flag "server.port". An inline table given for a
+// map-valued flag sets its entries instead. An array calls Set once per
+// element, as if the flag had been repeated on the command line.
+//
+// Values are passed to Set as text: integers in decimal, date-times in
+// RFC 3339 format, which time flags accept, and strings verbatim, so that
+// duration flags take strings such as "1m30s". Arrays of tables are not
+// supported.
+//
+// The values rank below the other layers described at Parse. Keys naming
+// no flag are handled as selected by UnknownKeys. Errors are of type
+// *ConfigError and include the line and column.
+func (f *FlagSet) LoadTOML(r io.Reader) error {
+	return f.loadTOML(r, "")
+}
+
+// LoadTOMLFile is like LoadTOML but reads the named file.
+func (f *FlagSet) LoadTOMLFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return &ConfigError{Source: path, Err: err}
+	}
+	defer file.Close()
+	return f.loadTOML(file, path)
+}
+
+// LoadTOML reads a TOML document from r and uses it to set the command-line
+// flags. See FlagSet.LoadTOML.
+func LoadTOML(r io.Reader) error {
+	return CommandLine.LoadTOML(r)
+}
+
+// LoadTOMLFile reads the named TOML file and uses it to set the
+// command-line flags. See FlagSet.LoadTOML.
+func LoadTOMLFile(path string) error {
+	return CommandLine.LoadTOMLFile(path)
+}
+
+func (f *FlagSet) loadTOML(r io.Reader, source string) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return &ConfigError{Source: source, Err: err}
+	}
+	p := &tomlParser{l: f.newConfigLoader(source), src: string(b), keys: make(map[string]bool), dotted: make(map[string]bool)}
+	if err := p.parse(); err != nil {
+		return err
+	}
+	return p.l.commit()
+}
+
+// tomlValue is a value read from a TOML document.
+type tomlValue struct {
+	pos   int         // offset of the value in the document
+	text  string      // text of a scalar, as passed to Value.Set
+	array []tomlValue // elements of an array
+	table []tomlKey   // entries of an inline table
+	kind  byte        // 's' for scalars, 'a' for arrays, 't' for inline tables
+}
+
+// tomlKey is an entry of an inline table.
+type tomlKey struct {
+	key   string
+	value tomlValue
+}
+
+type tomlParser struct {
+	l      *configLoader
+	src    string
+	pos    int
+	keys   map[string]bool // keys and tables already defined
+	dotted map[string]bool // tables defined by dotted keys
+}
+
+// where returns the line and column of the offset pos.
+func (p *tomlParser) where(pos int) string {
+	line := 1 + strings.Count(p.src[:pos], "\n")
+	col := 1 + utf8.RuneCountInString(p.src[strings.LastIndexByte(p.src[:pos], '\n')+1:pos])
+	return fmt.Sprintf("line %d, column %d", line, col)
+}
+
+func (p *tomlParser) errorf(pos int, format string, a ...interface{}) error {
+	return &ConfigError{Source: p.l.source, Path: p.where(pos), Err: fmt.Errorf(format, a...)}
+}
+
+func (p *tomlParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// skipSpace skips spaces and tabs.
+func (p *tomlParser) skipSpace() {
+	for !p.eof() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// skipComment skips a comment up to the end of the line.
+func (p *tomlParser) skipComment() {
+	if p.peek() == '#' {
+		for !p.eof() && p.src[p.pos] != '\n' {
+			p.pos++
+		}
+	}
+}
+
+// skipBlank skips whitespace, comments and newlines.
+func (p *tomlParser) skipBlank() {
+	for {
+		p.skipSpace()
+		p.skipComment()
+		switch p.peek() {
+		case '\n':
+			p.pos++
+		case '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// endOfLine consumes the rest of a line, which may only hold a comment.
+func (p *tomlParser) endOfLine() error {
+	p.skipSpace()
+	p.skipComment()
+	switch {
+	case p.eof():
+		return nil
+	case strings.HasPrefix(p.src[p.pos:], "\r\n"):
+		p.pos += 2
+		return nil
+	case p.src[p.pos] == '\n':
+		p.pos++
+		return nil
+	}
+	return p.errorf(p.pos, "expected end of line, found %q", p.src[p.pos])
+}
+
+func (p *tomlParser) parse() error {
+	table := ""
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil
+		}
+
+		start := p.pos
+		if p.peek() == '[' {
+			if strings.HasPrefix(p.src[p.pos:], "[[") {
+				return p.errorf(start, "arrays of tables are not supported")
+			}
+			p.pos++
+			p.skipSpace()
+			name, err := p.key()
+			if err != nil {
+				return err
+			}
+			p.skipSpace()
+			if p.peek() != ']' {
+				return p.errorf(p.pos, "expected ] after table name")
+			}
+			p.pos++
+			if p.keys[name] || p.dotted[name] {
+				return p.errorf(start, "table %s defined twice", name)
+			}
+			p.keys[name] = true
+			table = name + "."
+			if err := p.endOfLine(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key, err := p.key()
+		if err != nil {
+			return err
+		}
+		p.skipSpace()
+		if p.peek() != '=' {
+			return p.errorf(p.pos, "expected = after key %s", key)
+		}
+		p.pos++
+		p.skipSpace()
+		v, err := p.value()
+		if err != nil {
+			return err
+		}
+		if p.keys[table+key] || p.dotted[table+key] {
+			return p.errorf(start, "key %s defined twice", table+key)
+		}
+		for i := 0; i < len(key); i++ {
+			if key[i] != '.' {
+				continue
+			}
+			if p.keys[table+key[:i]] {
+				return p.errorf(start, "table %s defined twice", table+key[:i])
+			}
+			p.dotted[table+key[:i]] = true
+		}
+		p.keys[table+key] = true
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+		if err := p.set(table+key, v); err != nil {
+			return err
+		}
+	}
+}
+
+// set records the value v of the flag name.
+func (p *tomlParser) set(name string, v tomlValue) error {
+	if v.kind == 't' {
+		if flag, ok := p.l.f.formal[name]; ok {
+			if mv, ok := flag.Value.(*mapValue); ok {
+				for _, e := range v.table {
+					if e.value.kind != 's' {
+						return p.errorf(e.value.pos, "flag --%s: expected a string, number, boolean or date-time", name)
+					}
+					p.l.add(flag, p.where(e.value.pos), mv.pair(e.key, e.value.text))
+				}
+				return nil
+			}
+		}
+		for _, e := range v.table {
+			if err := p.set(name+"."+e.key, e.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	flag, err := p.l.lookup(name, p.where(v.pos))
+	if flag == nil {
+		return err
+	}
+	if v.kind == 's' {
+		p.l.add(flag, p.where(v.pos), v.text)
+		return nil
+	}
+	for _, e := range v.array {
+		if e.kind != 's' {
+			return p.errorf(e.pos, "flag --%s: nested arrays and tables are not supported", name)
+		}
+		p.l.add(flag, p.where(e.pos), literal(flag.Value, e.text))
+	}
+	return nil
+}
+
+// key parses a possibly dotted key and returns its parts joined with dots.
+func (p *tomlParser) key() (string, error) {
+	var parts []string
+	for {
+		start := p.pos
+		var part string
+		switch p.peek() {
+		case '"':
+			s, err := p.basicString()
+			if err != nil {
+				return "", err
+			}
+			part = s
+		case '\'':
+			s, err := p.literalString()
+			if err != nil {
+				return "", err
+			}
+			part = s
+		default:
+			for !p.eof() && isBareKeyChar(p.src[p.pos]) {
+				p.pos++
+			}
+			if p.pos == start {
+				return "", p.errorf(start, "expected a key")
+			}
+			part = p.src[start:p.pos]
+		}
+		parts = append(parts, part)
+
+		p.skipSpace()
+		if p.peek() != '.' {
+			return strings.Join(parts, "."), nil
+		}
+		p.pos++
+		p.skipSpace()
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) value() (tomlValue, error) {
+	start := p.pos
+	v := tomlValue{pos: start, kind: 's'}
+	var err error
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], `"""`):
+		v.text, err = p.multilineString(`"""`)
+	case strings.HasPrefix(p.src[p.pos:], "'''"):
+		v.text, err = p.multilineString("'''")
+	case p.peek() == '"':
+		v.text, err = p.basicString()
+	case p.peek() == '\'':
+		v.text, err = p.literalString()
+	case p.peek() == '[':
+		v.kind = 'a'
+		v.array, err = p.array()
+	case p.peek() == '{':
+		v.kind = 't'
+		v.table, err = p.inlineTable()
+	default:
+		v.text, err = p.atom()
+	}
+	return v, err
+}
+
+func (p *tomlParser) array() ([]tomlValue, error) {
+	start := p.pos
+	p.pos++ // '['
+	var elems []tomlValue
+	for {
+		p.skipBlank()
+		if p.peek() == ']' {
+			p.pos++
+			return elems, nil
+		}
+		if p.eof() {
+			return nil, p.errorf(start, "unterminated array")
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, v)
+		p.skipBlank()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, p.errorf(p.pos, "expected , or ] in array")
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable() ([]tomlKey, error) {
+	p.pos++ // '{'
+	var entries []tomlKey
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.pos++
+		return entries, nil
+	}
+	for {
+		p.skipSpace()
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.peek() != '=' {
+			return nil, p.errorf(p.pos, "expected = after key %s", key)
+		}
+		p.pos++
+		p.skipSpace()
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, tomlKey{key: key, value: v})
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return entries, nil
+		default:
+			return nil, p.errorf(p.pos, "expected , or } in inline table")
+		}
+	}
+}
+
+// basicString parses a double-quoted string with escape sequences.
+func (p *tomlParser) basicString() (string, error) {
+	start := p.pos
+	p.pos++ // '"'
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf(start, "unterminated string")
+		}
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// escape parses the escape sequence at the current position.
+func (p *tomlParser) escape(b *strings.Builder) error {
+	start := p.pos
+	p.pos++ // '\\'
+	if p.eof() {
+		return p.errorf(start, "unterminated escape sequence")
+	}
+	c := p.src[p.pos]
+	p.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case '"':
+		b.WriteByte('"')
+	case '\\':
+		b.WriteByte('\\')
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.src) {
+			return p.errorf(start, "bad unicode escape")
+		}
+		r, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(r)) {
+			return p.errorf(start, "bad unicode escape")
+		}
+		b.WriteRune(rune(r))
+		p.pos += n
+	default:
+		return p.errorf(start, "bad escape sequence \\%c", c)
+	}
+	return nil
+}
+
+// literalString parses a single-quoted string without escapes.
+func (p *tomlParser) literalString() (string, error) {
+	start := p.pos
+	p.pos++ // '\''
+	for !p.eof() && p.src[p.pos] != '\'' && p.src[p.pos] != '\n' {
+		p.pos++
+	}
+	if p.peek() != '\'' {
+		return "", p.errorf(start, "unterminated string")
+	}
+	p.pos++
+	return p.src[start+1 : p.pos-1], nil
+}
+
+// multilineString parses a multi-line basic or literal string, whose
+// delimiter is given by delim.
+func (p *tomlParser) multilineString(delim string) (string, error) {
+	start := p.pos
+	p.pos += 3
+	// A newline immediately following the opening delimiter is trimmed.
+	if strings.HasPrefix(p.src[p.pos:], "\r\n") {
+		p.pos += 2
+	} else if p.peek() == '\n' {
+		p.pos++
+	}
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf(start, "unterminated string")
+		}
+		if strings.HasPrefix(p.src[p.pos:], delim) {
+			// Up to two quotes may directly precede the closing delimiter.
+			for strings.HasPrefix(p.src[p.pos+1:], delim) {
+				b.WriteByte(delim[0])
+				p.pos++
+			}
+			p.pos += 3
+			return b.String(), nil
+		}
+		c := p.src[p.pos]
+		if c != '\\' || delim == "'''" {
+			b.WriteByte(c)
+			p.pos++
+			continue
+		}
+		// A backslash at the end of a line trims the following whitespace.
+		rest := strings.TrimLeft(p.src[p.pos+1:], " \t")
+		if strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n") {
+			p.pos = len(p.src) - len(strings.TrimLeft(rest, " \t\r\n"))
+			continue
+		}
+		if err := p.escape(&b); err != nil {
+			return "", err
+		}
+	}
+}
+
+// atom parses a boolean, number or date-time and returns it as text for
+// Value.Set.
+func (p *tomlParser) atom() (string, error) {
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(" \t\r\n,]}#", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	// A date and a time may be separated by a space.
+	if isTOMLDate(p.src[start:p.pos]) && strings.HasPrefix(p.src[p.pos:], " ") &&
+		p.pos+3 < len(p.src) && isDigit(p.src[p.pos+1]) && isDigit(p.src[p.pos+2]) && p.src[p.pos+3] == ':' {
+		p.pos++
+		for !p.eof() && !strings.ContainsRune(" \t\r\n,]}#", rune(p.src[p.pos])) {
+			p.pos++
+		}
+	}
+	s := p.src[start:p.pos]
+
+	switch {
+	case s == "":
+		return "", p.errorf(start, "expected a value")
+	case s == "true" || s == "false":
+		return s, nil
+	case isTOMLDate(s):
+		if len(s) > 10 && s[10] == ' ' {
+			s = s[:10] + "T" + s[11:]
+		}
+		return s, nil
+	case len(s) > 2 && isDigit(s[0]) && isDigit(s[1]) && s[2] == ':':
+		return s, nil
+	}
+
+	n := strings.TrimLeft(s, "+-")
+	if n == "inf" || n == "nan" {
+		if n == "nan" {
+			return "NaN", nil
+		}
+		if s[0] == '-' {
+			return "-Inf", nil
+		}
+		return "+Inf", nil
+	}
+	if strings.Contains(n, "__") || strings.HasPrefix(n, "_") || strings.HasSuffix(n, "_") {
+		return "", p.errorf(start, "bad number %s", s)
+	}
+	clean := strings.Replace(s, "_", "", -1)
+	if i, err := strconv.ParseInt(clean, 0, 64); err == nil {
+		if len(n) > 1 && n[0] == '0' && isDigit(n[1]) {
+			return "", p.errorf(start, "leading zeros are not allowed: %s", s)
+		}
+		return strconv.FormatInt(i, 10), nil
+	}
+	if !strings.HasPrefix(n, "0x") && !strings.HasPrefix(n, "0o") && !strings.HasPrefix(n, "0b") {
+		if _, err := strconv.ParseFloat(clean, 64); err == nil {
+			return clean, nil
+		}
+	}
+	return "", p.errorf(start, "invalid value %s", s)
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+// isTOMLDate reports whether s starts with a full date, YYYY-MM-DD.
+func isTOMLDate(s string) bool {
+	if len(s) < 10 || s[4] != '-' || s[7] != '-' {
+		return false
+	}
+	for _, i := range []int{0, 1, 2, 3, 5, 6, 8, 9} {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package flags_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/saihon/flags"
+)
+
+const tomlConfig = `# service settings
+title = "TOML \"example\""
+port = 8_080
+ratio = 0.5
+debug = true
+started = 1979-05-27T07:32:00Z
+tags = [
+	"a,b", # trailing comments are allowed
+	'c',
+]
+motd = """
+Roses are red
+Violets are blue"""
+server.timeout = "1m30s"
+
+[db]
+hosts = ["alpha", "omega"]
+limits = { cpu = 2, mem = 4 }
+"connection max" = 5000
+
+[db.pool]
+size = 0x10
+`
+
+func TestLoadTOML(t *testing.T) {
+	fs := NewFlagSet("toml test", ContinueOnError, false)
+	title := fs.String("title", 0, "", "", nil)
+	port := fs.Int("port", 0, 80, "", nil)
+	ratio := fs.Float64("ratio", 0, 0, "", nil)
+	debug := fs.Bool("debug", 0, false, "", nil)
+	started := fs.Time("started", 0, time.Time{}, "", nil)
+	tags := fs.StringSlice("tags", 0, nil, "", nil)
+	motd := fs.String("motd", 0, "", "", nil)
+	timeout := fs.Duration("server.timeout", 0, 0, "", nil)
+	hosts := fs.StringSlice("db.hosts", 0, nil, "", nil)
+	limits := fs.StringToInt("db.limits", 0, nil, "", nil)
+	max := fs.Int("db.connection max", 0, 0, "", nil)
+	size := fs.Int("db.pool.size", 0, 0, "", nil)
+
+	if err := fs.LoadTOML(strings.NewReader(tomlConfig)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"--port", "9090"}); err != nil {
+		t.Fatal(err)
+	}
+	if *title != `TOML "example"` || *port != 9090 || *ratio != 0.5 || !*debug {
+		t.Errorf("got title=%q port=%d ratio=%v debug=%t", *title, *port, *ratio, *debug)
+	}
+	if want := time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC); !started.Equal(want) {
+		t.Errorf("started = %v; want %v", *started, want)
+	}
+	if want := []string{"a,b", "c"}; !reflect.DeepEqual(*tags, want) {
+		t.Errorf("tags = %q; want %q", *tags, want)
+	}
+	if want := "Roses are red\nViolets are blue"; *motd != want {
+		t.Errorf("motd = %q; want %q", *motd, want)
+	}
+	if *timeout != 90*time.Second {
+		t.Errorf("server.timeout = %v; want 1m30s", *timeout)
+	}
+	if want := []string{"alpha", "omega"}; !reflect.DeepEqual(*hosts, want) {
+		t.Errorf("db.hosts = %q; want %q", *hosts, want)
+	}
+	if want := map[string]int{"cpu": 2, "mem": 4}; !reflect.DeepEqual(*limits, want) {
+		t.Errorf("db.limits = %v; want %v", *limits, want)
+	}
+	if *max != 5000 || *size != 16 {
+		t.Errorf("got connection max=%d pool.size=%d", *max, *size)
+	}
+}
+
+func TestLoadTOMLErrors(t *testing.T) {
+	data := []struct {
+		config string
+		path   string
+	}{
+		{config: "port = 1\nport = 2\n", path: "line 2, column 1"},
+		{config: "port = \"80\n", path: "line 1, column 8"},
+		{config: "\n  port = 08\n", path: "line 2, column 10"},
+		{config: "[server\nport = 1\n", path: "line 1, column 8"},
+		{config: "[[servers]]\n", path: "line 1, column 1"},
+		{config: "server.port = 1\n[server]\n", path: "line 2, column 1"},
+		{config: "server.port = 1\nserver = 2\n", path: "line 2, column 1"},
+		{config: "[server.tls]\n[server]\ntls.cert = 1\n", path: "line 3, column 1"},
+		{config: "name = 1\n", path: "line 1, column 8"},
+		{config: "port = 1 2\n", path: "line 1, column 10"},
+		{config: "port = 1.5\n", path: "line 1, column 8"},
+	}
+
+	for _, v := range data {
+		fs := NewFlagSet("toml test", ContinueOnError, false)
+		fs.Int("port", 0, 0, "", nil)
+		fs.Int("server.port", 0, 0, "", nil)
+		err := fs.LoadTOML(strings.NewReader(v.config))
+		if err == nil {
+			err = fs.Parse(nil)
+		}
+		if ce, ok := err.(*ConfigError); !ok || ce.Path != v.path {
+			t.Errorf("%q: got %v; want error at %s", v.config, err, v.path)
+		}
+	}
+}
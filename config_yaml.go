@@ -0,0 +1,550 @@
+package flags
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadYAML reads a YAML document from r and uses it to set the flags of the
+// flag set. Keys name flags by their long name, and the keys of nested
+// mappings are joined with dots, so that "port" under "server:" sets the
+// flag "server.port". A mapping given for a map-valued flag sets its
+// entries instead. A sequence calls Set once per element, as if the flag
+// had been repeated on the command line. Null values are ignored.
+//
+// Only the commonly used subset of YAML is supported: block mappings and
+// sequences, flow sequences and mappings of scalars on a single line,
+// plain, single- and double-quoted scalars, literal (|) and folded (>)
+// block scalars, comments, and anchors (&name) with aliases (*name).
+// Tags, multi-line plain scalars and merge keys are not.
+//
+// The values rank below the other layers described at Parse. Keys naming
+// no flag are handled as selected by UnknownKeys. Errors are of type
+// *ConfigError and include the line and column.
+func (f *FlagSet) LoadYAML(r io.Reader) error {
+	return f.loadYAML(r, "")
+}
+
+// LoadYAMLFile is like LoadYAML but reads the named file.
+func (f *FlagSet) LoadYAMLFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return &ConfigError{Source: path, Err: err}
+	}
+	defer file.Close()
+	return f.loadYAML(file, path)
+}
+
+// LoadYAML reads a YAML document from r and uses it to set the command-line
+// flags. See FlagSet.LoadYAML.
+func LoadYAML(r io.Reader) error {
+	return CommandLine.LoadYAML(r)
+}
+
+// LoadYAMLFile reads the named YAML file and uses it to set the
+// command-line flags. See FlagSet.LoadYAML.
+func LoadYAMLFile(path string) error {
+	return CommandLine.LoadYAMLFile(path)
+}
+
+func (f *FlagSet) loadYAML(r io.Reader, source string) error {
+	p := &yamlParser{l: f.newConfigLoader(source), anchors: make(map[string]*yamlNode)}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		p.lines = append(p.lines, strings.TrimSuffix(sc.Text(), "\r"))
+	}
+	if err := sc.Err(); err != nil {
+		return &ConfigError{Source: source, Err: err}
+	}
+
+	root, err := p.parseBlock(0)
+	if err != nil {
+		return err
+	}
+	if _, text, ok, _ := p.peek(); ok {
+		return p.errorf(p.i, 0, "unexpected %q", text)
+	}
+	if root == nil {
+		return p.l.commit()
+	}
+	if root.kind != 'm' {
+		return p.errorf(root.line, root.col, "expected a mapping")
+	}
+	if err := p.walk("", root); err != nil {
+		return err
+	}
+	return p.l.commit()
+}
+
+// yamlNode is a node of a YAML document. A nil *yamlNode is a null.
+type yamlNode struct {
+	line, col int         // position, 0-based
+	kind      byte        // 's' for scalars, 'a' for sequences, 'm' for mappings
+	text      string      // text of a scalar
+	items     []*yamlNode // elements of a sequence or values of a mapping
+	keys      []string    // keys of a mapping
+}
+
+type yamlParser struct {
+	l       *configLoader
+	lines   []string
+	i       int // index of the next line
+	anchors map[string]*yamlNode
+}
+
+func (p *yamlParser) where(line, col int) string {
+	return fmt.Sprintf("line %d, column %d", line+1, col+1)
+}
+
+func (p *yamlParser) errorf(line, col int, format string, a ...interface{}) error {
+	return &ConfigError{Source: p.l.source, Path: p.where(line, col), Err: fmt.Errorf(format, a...)}
+}
+
+// peek returns the indentation and content of the next line that holds
+// anything but comments, skipping the lines before it.
+func (p *yamlParser) peek() (indent int, text string, ok bool, err error) {
+	for ; p.i < len(p.lines); p.i++ {
+		raw := p.lines[p.i]
+		if strings.HasPrefix(raw, "---") || strings.HasPrefix(raw, "...") || strings.HasPrefix(raw, "%") {
+			continue
+		}
+		text := strings.TrimRight(stripYAMLComment(raw), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" {
+			continue
+		}
+		if trimmed[0] == '\t' {
+			return 0, "", false, p.errorf(p.i, len(text)-len(trimmed), "tabs are not allowed in indentation")
+		}
+		return len(text) - len(trimmed), trimmed, true, nil
+	}
+	return 0, "", false, nil
+}
+
+// stripYAMLComment removes a comment from a line. A comment starts with
+// '#' at the start of the line or after whitespace, outside of quotes.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t[{,", s[i-1]) >= 0):
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func isYAMLSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey splits "key: rest" and reports whether text is a mapping entry.
+func splitYAMLKey(text string) (key, rest string, ok bool) {
+	if text == "" || strings.IndexByte("[{&*|>", text[0]) >= 0 || isYAMLSeqItem(text) {
+		return "", "", false
+	}
+	end := 0
+	if text[0] == '"' || text[0] == '\'' {
+		end = closingQuote(text)
+		if end < 0 {
+			return "", "", false
+		}
+		end++
+		for end < len(text) && text[end] == ' ' {
+			end++
+		}
+		if end >= len(text) || text[end] != ':' {
+			return "", "", false
+		}
+	} else {
+		end = strings.Index(text, ": ")
+		if end < 0 {
+			if !strings.HasSuffix(text, ":") {
+				return "", "", false
+			}
+			end = len(text) - 1
+		}
+	}
+	key, err := yamlScalar(strings.TrimRight(text[:end], " "))
+	if err != nil {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(text[end+1:]), true
+}
+
+// closingQuote returns the index of the quote closing the one at s[0], or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[0] == '"' && s[i] == '\\':
+			i++
+		case s[i] == s[0] && s[0] == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == s[0]:
+			return i
+		}
+	}
+	return -1
+}
+
+// yamlScalar returns the value of a plain or quoted scalar.
+func yamlScalar(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	switch s[0] {
+	case '"':
+		if closingQuote(s) != len(s)-1 {
+			return "", fmt.Errorf("bad double-quoted scalar %s", s)
+		}
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("bad double-quoted scalar %s", s)
+		}
+		return v, nil
+	case '\'':
+		if closingQuote(s) != len(s)-1 {
+			return "", fmt.Errorf("bad single-quoted scalar %s", s)
+		}
+		return strings.Replace(s[1:len(s)-1], "''", "'", -1), nil
+	}
+	return s, nil
+}
+
+// parseBlock parses the node starting on the next line, if it is indented
+// by at least minIndent.
+func (p *yamlParser) parseBlock(minIndent int) (*yamlNode, error) {
+	indent, text, ok, err := p.peek()
+	if err != nil || !ok || indent < minIndent {
+		return nil, err
+	}
+	if isYAMLSeqItem(text) {
+		return p.parseSeq(indent)
+	}
+	if _, _, ok := splitYAMLKey(text); ok {
+		return p.parseMap(indent)
+	}
+	line := p.i
+	p.i++
+	return p.value(text, indent, line, indent, false)
+}
+
+func (p *yamlParser) parseSeq(indent int) (*yamlNode, error) {
+	node := &yamlNode{line: p.i, col: indent, kind: 'a'}
+	for {
+		in, text, ok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || in < indent || (in == indent && !isYAMLSeqItem(text)) {
+			return node, nil
+		}
+		if in > indent {
+			return nil, p.errorf(p.i, in, "bad indentation")
+		}
+
+		rest := strings.TrimLeft(text[1:], " ")
+		itemIndent := in + len(text) - len(rest)
+		var item *yamlNode
+		if _, _, isKey := splitYAMLKey(rest); isKey || isYAMLSeqItem(rest) {
+			// Read the line again with the dash replaced by a space so
+			// that the rest of the item lines up with its first entry.
+			p.lines[p.i] = strings.Repeat(" ", itemIndent) + rest
+			item, err = p.parseBlock(itemIndent)
+		} else {
+			line := p.i
+			p.i++
+			item, err = p.value(rest, in, line, itemIndent, false)
+		}
+		if err != nil {
+			return nil, err
+		}
+		node.items = append(node.items, item)
+	}
+}
+
+func (p *yamlParser) parseMap(indent int) (*yamlNode, error) {
+	node := &yamlNode{line: p.i, col: indent, kind: 'm'}
+	seen := make(map[string]bool)
+	for {
+		in, text, ok, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || in < indent || (in == indent && isYAMLSeqItem(text)) {
+			return node, nil
+		}
+		if in > indent {
+			return nil, p.errorf(p.i, in, "bad indentation")
+		}
+		key, rest, ok := splitYAMLKey(text)
+		if !ok {
+			return nil, p.errorf(p.i, in, "expected key: value")
+		}
+		if seen[key] {
+			return nil, p.errorf(p.i, in, "key %s defined twice", key)
+		}
+		seen[key] = true
+
+		line := p.i
+		p.i++
+		value, err := p.value(rest, indent, line, in+len(text)-len(rest), true)
+		if err != nil {
+			return nil, err
+		}
+		node.keys = append(node.keys, key)
+		node.items = append(node.items, value)
+	}
+}
+
+// value parses the value text found at line and col of an entry at the
+// given indentation, together with any block it introduces.
+func (p *yamlParser) value(text string, indent, line, col int, inMap bool) (*yamlNode, error) {
+	anchor := ""
+	if strings.HasPrefix(text, "&") {
+		i := strings.IndexAny(text, " \t")
+		if i < 0 {
+			i = len(text)
+		}
+		anchor, text = text[1:i], strings.TrimLeft(text[i:], " \t")
+		col += i
+		if anchor == "" {
+			return nil, p.errorf(line, col, "empty anchor name")
+		}
+	}
+
+	var node *yamlNode
+	var err error
+	switch {
+	case text == "":
+		in, next, ok, perr := p.peek()
+		switch {
+		case perr != nil:
+			err = perr
+		case ok && in > indent:
+			node, err = p.parseBlock(indent + 1)
+		case ok && in == indent && inMap && isYAMLSeqItem(next):
+			node, err = p.parseSeq(indent)
+		}
+	case text[0] == '*':
+		var ok bool
+		if node, ok = p.anchors[text[1:]]; !ok {
+			err = p.errorf(line, col, "unknown alias %s", text)
+		}
+	case text[0] == '|' || text[0] == '>':
+		node, err = p.blockScalar(text, indent, line, col)
+	case text[0] == '[' || text[0] == '{':
+		node, err = p.flow(text, line, col)
+	case text == "~" || text == "null" || text == "Null" || text == "NULL":
+	default:
+		s, serr := yamlScalar(text)
+		if serr != nil {
+			err = p.errorf(line, col, "%v", serr)
+		}
+		node = &yamlNode{line: line, col: col, kind: 's', text: s}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if anchor != "" {
+		p.anchors[anchor] = node
+	}
+	return node, nil
+}
+
+// blockScalar reads a literal or folded block scalar whose header is text.
+func (p *yamlParser) blockScalar(text string, indent, line, col int) (*yamlNode, error) {
+	chomp := ""
+	if len(text) > 1 {
+		chomp = text[1:]
+	}
+	if chomp != "" && chomp != "-" && chomp != "+" {
+		return nil, p.errorf(line, col, "unsupported block scalar header %s", text)
+	}
+
+	var lines []string
+	contentIndent := -1
+	for ; p.i < len(p.lines); p.i++ {
+		raw := p.lines[p.i]
+		trimmed := strings.TrimLeft(raw, " ")
+		if trimmed == "" {
+			lines = append(lines, "")
+			continue
+		}
+		in := len(raw) - len(trimmed)
+		if contentIndent < 0 {
+			if in <= indent {
+				break
+			}
+			contentIndent = in
+		}
+		if in < contentIndent {
+			break
+		}
+		lines = append(lines, raw[contentIndent:])
+	}
+
+	// Trailing blank lines belong to the scalar only when kept.
+	end := len(lines)
+	for end > 0 && lines[end-1] == "" {
+		end--
+	}
+	trailing := len(lines) - end
+	lines = lines[:end]
+
+	var s string
+	if text[0] == '|' {
+		s = strings.Join(lines, "\n")
+	} else {
+		for i, l := range lines {
+			switch {
+			case i == 0:
+			case l == "" || lines[i-1] == "":
+				s += "\n"
+			default:
+				s += " "
+			}
+			s += l
+		}
+	}
+	switch {
+	case chomp == "+":
+		s += strings.Repeat("\n", trailing+1)
+	case chomp == "" && len(lines) > 0:
+		s += "\n"
+	}
+	return &yamlNode{line: line, col: col, kind: 's', text: s}, nil
+}
+
+// flow parses a flow sequence or mapping of scalars on a single line.
+func (p *yamlParser) flow(text string, line, col int) (*yamlNode, error) {
+	open, close := text[0], byte(']')
+	kind := byte('a')
+	if open == '{' {
+		close, kind = '}', 'm'
+	}
+	if text[len(text)-1] != close {
+		return nil, p.errorf(line, col, "flow collections must end on the same line")
+	}
+
+	node := &yamlNode{line: line, col: col, kind: kind}
+	inner := text[1 : len(text)-1]
+	start := 0
+	for i := 0; i <= len(inner); i++ {
+		if i < len(inner) {
+			c := inner[i]
+			switch {
+			case c == '"' || c == '\'':
+				j := closingQuote(inner[i:])
+				if j < 0 {
+					return nil, p.errorf(line, col+1+i, "unterminated quoted scalar")
+				}
+				i += j
+				continue
+			case c == '[' || c == '{':
+				return nil, p.errorf(line, col+1+i, "nested flow collections are not supported")
+			case c != ',':
+				continue
+			}
+		}
+
+		item := strings.TrimSpace(inner[start:i])
+		itemCol := col + 1 + start + len(inner[start:i]) - len(strings.TrimLeft(inner[start:i], " "))
+		start = i + 1
+		if item == "" {
+			if i == len(inner) {
+				break // trailing comma
+			}
+			return nil, p.errorf(line, itemCol, "empty flow collection entry")
+		}
+		var key string
+		if kind == 'm' {
+			var ok bool
+			if key, item, ok = splitYAMLKey(item); !ok {
+				return nil, p.errorf(line, itemCol, "expected key: value")
+			}
+		}
+		value, err := yamlScalar(item)
+		if err != nil {
+			return nil, p.errorf(line, itemCol, "%v", err)
+		}
+		node.keys = append(node.keys, key)
+		node.items = append(node.items, &yamlNode{line: line, col: itemCol, kind: 's', text: value})
+	}
+	if kind == 'a' {
+		node.keys = nil
+	}
+	return node, nil
+}
+
+// walk records the values of the mapping node, whose keys are prefixed with prefix.
+func (p *yamlParser) walk(prefix string, node *yamlNode) error {
+	for i, key := range node.keys {
+		name, v := prefix+key, node.items[i]
+		if v == nil {
+			continue
+		}
+		path := p.where(v.line, v.col)
+		switch v.kind {
+		case 'm':
+			if flag, ok := p.l.f.formal[name]; ok {
+				if mv, ok := flag.Value.(*mapValue); ok {
+					for j, k := range v.keys {
+						e := v.items[j]
+						if e == nil {
+							continue
+						}
+						if e.kind != 's' {
+							return p.errorf(e.line, e.col, "flag --%s: expected a scalar", name)
+						}
+						p.l.add(flag, p.where(e.line, e.col), mv.pair(k, e.text))
+					}
+					continue
+				}
+			}
+			if err := p.walk(name+".", v); err != nil {
+				return err
+			}
+		case 'a':
+			flag, err := p.l.lookup(name, path)
+			if flag == nil {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			for _, e := range v.items {
+				if e == nil {
+					continue
+				}
+				if e.kind != 's' {
+					return p.errorf(e.line, e.col, "flag --%s: nested collections are not supported", name)
+				}
+				p.l.add(flag, p.where(e.line, e.col), literal(flag.Value, e.text))
+			}
+		default:
+			flag, err := p.l.lookup(name, path)
+			if flag == nil {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			p.l.add(flag, path, v.text)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package flags_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+const yamlConfig = `# server configuration
+---
+port: 8080
+host: "file.example.com"  # quoted
+verbose: true
+server:
+  read-timeout: &timeout 5s
+  write-timeout: *timeout
+  tls:
+    cert: 'it''s.pem'
+tags:
+- a,b
+- c
+peers: [one, "two, three"]
+labels: {env: prod, team: core}
+limits:
+  cpu: 2
+  mem: 4
+motd: |
+  hello # not a comment
+  world
+banner: >-
+  folded
+  text
+unused: ~
+`
+
+func TestLoadYAML(t *testing.T) {
+	fs := NewFlagSet("yaml test", ContinueOnError, false)
+	port := fs.Int("port", 'p', 80, "", nil)
+	host := fs.String("host", 0, "localhost", "", nil)
+	verbose := fs.Bool("verbose", 0, false, "", nil)
+	read := fs.Duration("server.read-timeout", 0, 0, "", nil)
+	write := fs.Duration("server.write-timeout", 0, 0, "", nil)
+	cert := fs.String("server.tls.cert", 0, "", "", nil)
+	tags := fs.StringSlice("tags", 0, nil, "", nil)
+	peers := fs.StringSlice("peers", 0, nil, "", nil)
+	labels := fs.StringToString("labels", 0, nil, "", nil)
+	limits := fs.StringToInt("limits", 0, nil, "", nil)
+	motd := fs.String("motd", 0, "", "", nil)
+	banner := fs.String("banner", 0, "", "", nil)
+	unused := fs.String("unused", 0, "default", "", nil)
+
+	if err := fs.LoadYAML(strings.NewReader(yamlConfig)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-p", "9090"}); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 9090 {
+		t.Errorf("port = %d; command line should win over the file", *port)
+	}
+	if *host != "file.example.com" || !*verbose || read.String() != "5s" || write.String() != "5s" || *cert != "it's.pem" {
+		t.Errorf("got host=%q verbose=%t read=%v write=%v cert=%q", *host, *verbose, *read, *write, *cert)
+	}
+	if want := []string{"a,b", "c"}; !reflect.DeepEqual(*tags, want) {
+		t.Errorf("tags = %q; want %q", *tags, want)
+	}
+	if want := []string{"one", "two, three"}; !reflect.DeepEqual(*peers, want) {
+		t.Errorf("peers = %q; want %q", *peers, want)
+	}
+	if want := map[string]string{"env": "prod", "team": "core"}; !reflect.DeepEqual(*labels, want) {
+		t.Errorf("labels = %v; want %v", *labels, want)
+	}
+	if want := map[string]int{"cpu": 2, "mem": 4}; !reflect.DeepEqual(*limits, want) {
+		t.Errorf("limits = %v; want %v", *limits, want)
+	}
+	if want := "hello # not a comment\nworld\n"; *motd != want {
+		t.Errorf("motd = %q; want %q", *motd, want)
+	}
+	if want := "folded text"; *banner != want {
+		t.Errorf("banner = %q; want %q", *banner, want)
+	}
+	if *unused != "default" {
+		t.Errorf("unused = %q; null should leave the default", *unused)
+	}
+}
+
+func TestLoadYAMLSequenceOfMappings(t *testing.T) {
+	fs := NewFlagSet("yaml test", ContinueOnError, false)
+	fs.String("name", 0, "", "", nil)
+	err := fs.LoadYAML(strings.NewReader("name:\n  - key: value\n"))
+	if ce, ok := err.(*ConfigError); !ok || ce.Path != "line 2, column 5" {
+		t.Errorf("got %#v; want error at line 2, column 5", err)
+	}
+}
+
+func TestLoadYAMLErrors(t *testing.T) {
+	data := []struct {
+		config string
+		path   string
+		flag   string
+	}{
+		{config: "server:\n  port: x\n", path: "line 2, column 9", flag: "server.port"},
+		{config: "server:\n  port: 1\n   name: 2\n", path: "line 3, column 4"},
+		{config: "port: 1\nport: 2\n", path: "line 2, column 1"},
+		{config: "port: *missing\n", path: "line 1, column 7"},
+		{config: "port: [1, [2]]\n", path: "line 1, column 11"},
+		{config: "server:\n\tport: 1\n", path: "line 2, column 1"},
+		{config: "- 1\n", path: "line 1, column 1"},
+		{config: "server:\n  name: 1\n", path: "line 2, column 9"},
+	}
+
+	for _, v := range data {
+		fs := NewFlagSet("yaml test", ContinueOnError, false)
+		fs.Int("server.port", 0, 0, "", nil)
+		fs.Int("port", 0, 0, "", nil)
+
+		err := fs.LoadYAML(strings.NewReader(v.config))
+		if err == nil {
+			err = fs.Parse(nil)
+		}
+		if ce, ok := err.(*ConfigError); !ok || ce.Path != v.path || ce.Flag != v.flag {
+			t.Errorf("%q: got %#v; want path %q flag %q", v.config, err, v.path, v.flag)
+		}
+	}
+}
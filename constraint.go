@@ -0,0 +1,201 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConstraintKind identifies a kind of constraint between flags.
+type ConstraintKind int
+
+// These constants identify the constraints that can be declared on a FlagSet.
+const (
+	MutuallyExclusiveFlags ConstraintKind = iota // at most one of the flags may be set
+	RequiredTogetherFlags                        // all or none of the flags must be set
+	AtLeastOneOfFlags                            // at least one of the flags must be set
+	RequiredIfFlag                               // a flag must be set if another has a value
+)
+
+// A ConstraintError is returned by Parse when the arguments violate a
+// constraint declared on the FlagSet.
+type ConstraintError struct {
+	Kind  ConstraintKind
+	Flags []string // names of the flags the constraint applies to
+
+	// Offending names the flags that were set in violation of a
+	// MutuallyExclusiveFlags constraint, or the flags that are missing
+	// for the other kinds.
+	Offending []string
+
+	// Value is the value of Flags[1] that makes Flags[0] required
+	// for a RequiredIfFlag constraint.
+	Value string
+}
+
+func (e *ConstraintError) Error() string {
+	switch e.Kind {
+	case MutuallyExclusiveFlags:
+		return fmt.Sprintf("flags %s are mutually exclusive", flagList(e.Offending, "and"))
+	case RequiredTogetherFlags:
+		return fmt.Sprintf("flags %s must be set together; missing %s", flagList(e.Flags, "and"), flagList(e.Offending, "and"))
+	case AtLeastOneOfFlags:
+		return fmt.Sprintf("at least one of the flags %s is required", flagList(e.Flags, "or"))
+	case RequiredIfFlag:
+		return fmt.Sprintf("flag --%s is required when --%s is %s", e.Flags[0], e.Flags[1], e.Value)
+	}
+	return fmt.Sprintf("flag constraint %d violated", e.Kind)
+}
+
+// A constraint is a constraint declared on a FlagSet.
+type constraint struct {
+	kind  ConstraintKind
+	flags []string
+	value string // for RequiredIfFlag
+}
+
+// note describes the constraint in a usage message.
+func (c *constraint) note() string {
+	switch c.kind {
+	case MutuallyExclusiveFlags:
+		return fmt.Sprintf("%s are mutually exclusive", flagList(c.flags, "and"))
+	case RequiredTogetherFlags:
+		return fmt.Sprintf("%s must be set together", flagList(c.flags, "and"))
+	case AtLeastOneOfFlags:
+		return fmt.Sprintf("at least one of %s is required", flagList(c.flags, "or"))
+	case RequiredIfFlag:
+		return fmt.Sprintf("--%s is required when --%s is %s", c.flags[0], c.flags[1], c.value)
+	}
+	return ""
+}
+
+// flagList formats names as "--a, --b and --c".
+func flagList(names []string, conj string) string {
+	s := make([]string, len(names))
+	for i, name := range names {
+		s[i] = "--" + name
+	}
+	if len(s) < 2 {
+		return strings.Join(s, "")
+	}
+	return strings.Join(s[:len(s)-1], ", ") + " " + conj + " " + s[len(s)-1]
+}
+
+// addConstraint records a constraint after checking that its flags exist.
+func (f *FlagSet) addConstraint(c *constraint) {
+	for _, name := range c.flags {
+		if _, ok := f.formal[name]; !ok {
+			var msg string
+			if f.name == "" {
+				msg = fmt.Sprintf("flag constraint names undefined flag: %s", name)
+			} else {
+				msg = fmt.Sprintf("%s flag constraint names undefined flag: %s", f.name, name)
+			}
+			fmt.Fprintln(f.Output(), msg)
+			panic(msg) // Happens only if constraints are declared before their flags
+		}
+	}
+	f.constraints = append(f.constraints, c)
+}
+
+// MutuallyExclusive declares that at most one of the named flags may be set.
+// The flags must be defined before the constraint is declared; it panics
+// if one of them is not.
+func (f *FlagSet) MutuallyExclusive(names ...string) {
+	f.addConstraint(&constraint{kind: MutuallyExclusiveFlags, flags: names})
+}
+
+// RequiredTogether declares that if any of the named flags is set, all of
+// them must be. The flags must be defined before the constraint is
+// declared; it panics if one of them is not.
+func (f *FlagSet) RequiredTogether(names ...string) {
+	f.addConstraint(&constraint{kind: RequiredTogetherFlags, flags: names})
+}
+
+// AtLeastOneOf declares that at least one of the named flags must be set.
+// The flags must be defined before the constraint is declared; it panics
+// if one of them is not.
+func (f *FlagSet) AtLeastOneOf(names ...string) {
+	f.addConstraint(&constraint{kind: AtLeastOneOfFlags, flags: names})
+}
+
+// RequiredIf declares that the flag name must be set whenever the flag
+// other has the given value, as in RequiredIf("tls-cert", "tls", "true").
+// The value is compared with the String method of the other flag's Value.
+// Both flags must be defined before the constraint is declared; it panics
+// if one of them is not.
+func (f *FlagSet) RequiredIf(name, other, value string) {
+	f.addConstraint(&constraint{kind: RequiredIfFlag, flags: []string{name, other}, value: value})
+}
+
+// MutuallyExclusive declares that at most one of the named command-line
+// flags may be set. The flags must already be defined.
+func MutuallyExclusive(names ...string) {
+	CommandLine.MutuallyExclusive(names...)
+}
+
+// RequiredTogether declares that if any of the named command-line flags
+// is set, all of them must be. The flags must already be defined.
+func RequiredTogether(names ...string) {
+	CommandLine.RequiredTogether(names...)
+}
+
+// AtLeastOneOf declares that at least one of the named command-line flags
+// must be set. The flags must already be defined.
+func AtLeastOneOf(names ...string) {
+	CommandLine.AtLeastOneOf(names...)
+}
+
+// RequiredIf declares that the command-line flag name must be set whenever
+// the flag other has the given value. The flags must already be defined.
+func RequiredIf(name, other, value string) {
+	CommandLine.RequiredIf(name, other, value)
+}
+
+// checkConstraints returns an error for the first violated constraint.
+func (f *FlagSet) checkConstraints() error {
+	for _, c := range f.constraints {
+		var set, unset []string
+		for _, name := range c.flags {
+			if _, ok := f.actual[name]; ok {
+				set = append(set, name)
+			} else {
+				unset = append(unset, name)
+			}
+		}
+
+		var offending []string
+		switch c.kind {
+		case MutuallyExclusiveFlags:
+			if len(set) > 1 {
+				offending = set
+			}
+		case RequiredTogetherFlags:
+			if len(set) > 0 {
+				offending = unset
+			}
+		case AtLeastOneOfFlags:
+			if len(set) == 0 {
+				offending = unset
+			}
+		case RequiredIfFlag:
+			if _, ok := f.actual[c.flags[0]]; !ok && f.formal[c.flags[1]].Value.String() == c.value {
+				offending = c.flags[:1]
+			}
+		}
+		if len(offending) > 0 {
+			return f.fail(&ConstraintError{Kind: c.kind, Flags: c.flags, Offending: offending, Value: c.value})
+		}
+	}
+	return nil
+}
+
+// printConstraints prints a note for each constraint declared on the flag set.
+func (f *FlagSet) printConstraints() {
+	if len(f.constraints) == 0 {
+		return
+	}
+	fmt.Fprintf(f.Output(), "\nNotes:\n")
+	for _, c := range f.constraints {
+		fmt.Fprintf(f.Output(), "  %s\n", c.note())
+	}
+}
@@ -0,0 +1,95 @@
+package flags_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+func newConstraintFlagSet() *FlagSet {
+	fs := NewFlagSet("constraint test", ContinueOnError, false)
+	fs.SetOutput(ioutil.Discard)
+	fs.Bool("json", 0, false, "", nil)
+	fs.Bool("yaml", 0, false, "", nil)
+	fs.String("user", 0, "", "", nil)
+	fs.String("password", 0, "", "", nil)
+	fs.String("file", 0, "", "", nil)
+	fs.String("url", 0, "", "", nil)
+	fs.Bool("tls", 0, false, "", nil)
+	fs.String("tls-cert", 0, "", "", nil)
+	fs.MutuallyExclusive("json", "yaml")
+	fs.RequiredTogether("user", "password")
+	fs.AtLeastOneOf("file", "url")
+	fs.RequiredIf("tls-cert", "tls", "true")
+	return fs
+}
+
+func TestConstraints(t *testing.T) {
+	data := []struct {
+		a         []string
+		kind      ConstraintKind
+		offending []string
+		msg       string
+	}{
+		{
+			a:         []string{"--url=x", "--json", "--yaml"},
+			kind:      MutuallyExclusiveFlags,
+			offending: []string{"json", "yaml"},
+			msg:       "flags --json and --yaml are mutually exclusive",
+		},
+		{
+			a:         []string{"--file=x", "--user=me"},
+			kind:      RequiredTogetherFlags,
+			offending: []string{"password"},
+			msg:       "flags --user and --password must be set together; missing --password",
+		},
+		{
+			a:         []string{"--json"},
+			kind:      AtLeastOneOfFlags,
+			offending: []string{"file", "url"},
+			msg:       "at least one of the flags --file or --url is required",
+		},
+		{
+			a:         []string{"--file=x", "--tls"},
+			kind:      RequiredIfFlag,
+			offending: []string{"tls-cert"},
+			msg:       "flag --tls-cert is required when --tls is true",
+		},
+	}
+
+	for _, v := range data {
+		err := newConstraintFlagSet().Parse(v.a)
+		ce, ok := err.(*ConstraintError)
+		if !ok {
+			t.Errorf("Parse(%q) = %v; want *ConstraintError", v.a, err)
+			continue
+		}
+		if ce.Kind != v.kind || !reflect.DeepEqual(ce.Offending, v.offending) || ce.Error() != v.msg {
+			t.Errorf("Parse(%q) = %v %q %q; want %v %q %q", v.a, ce.Kind, ce.Offending, ce.Error(), v.kind, v.offending, v.msg)
+		}
+	}
+
+	ok := []string{"--url=x", "--json", "--user=me", "--password=pw", "--tls", "--tls-cert=c"}
+	if err := newConstraintFlagSet().Parse(ok); err != nil {
+		t.Errorf("Parse(%q) = %v; want nil", ok, err)
+	}
+}
+
+func TestConstraintNotes(t *testing.T) {
+	fs := newConstraintFlagSet()
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.Usage()
+	want := "\nNotes:\n" +
+		"  --json and --yaml are mutually exclusive\n" +
+		"  --user and --password must be set together\n" +
+		"  at least one of --file or --url is required\n" +
+		"  --tls-cert is required when --tls is true\n"
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("usage = %q; want suffix %q", buf.String(), want)
+	}
+}
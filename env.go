@@ -0,0 +1,56 @@
+package flags
+
+import (
+	"os"
+	"strings"
+	"unicode"
+)
+
+// envName returns the environment variable name derived from a flag name
+// and prefix: EnvPrefix "MYAPP_" and the flag "listen-addr" give
+// MYAPP_LISTEN_ADDR.
+func envName(prefix, name string) string {
+	return prefix + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
+
+// envVars returns the environment variables consulted for flag, in order.
+func (f *FlagSet) envVars(flag *Flag) []string {
+	if f.EnvPrefix == "" {
+		return flag.EnvVars
+	}
+	return append(append([]string(nil), flag.EnvVars...), envName(f.EnvPrefix, flag.Name))
+}
+
+// applyEnv sets each flag not given on the command line from the first of
+// its environment variables that is set.
+func (f *FlagSet) applyEnv() error {
+	for _, flag := range sortFlags(f.formal) {
+		if _, ok := f.actual[flag.Name]; ok {
+			continue
+		}
+		for _, key := range f.envVars(flag) {
+			value, ok := os.LookupEnv(key)
+			if !ok {
+				continue
+			}
+			if err := flag.Value.Set(value); err != nil {
+				return f.failf("invalid value %q for flag --%s from $%s: %v", value, flag.Name, key, err)
+			}
+			flag.record(OriginEnv, value, "$"+key)
+			if err := f.callback(flag); err != nil {
+				return err
+			}
+			if f.actual == nil {
+				f.actual = make(map[string]*Flag)
+			}
+			f.actual[flag.Name] = flag
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package flags_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+func setenv(t *testing.T, key, value string) {
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestEnvFallback(t *testing.T) {
+	setenv(t, "MYAPP_PORT", "8080")
+	setenv(t, "MYAPP_LISTEN_ADDR", "0.0.0.0")
+	setenv(t, "API_TOKEN", "secret")
+	setenv(t, "MYAPP_VERBOSE", "true")
+
+	fs := NewFlagSet("env test", ContinueOnError, false)
+	fs.EnvPrefix = "MYAPP_"
+	var called bool
+	port := fs.Int("port", 'p', 80, "listen port", func(g Getter) error {
+		called = true
+		return nil
+	})
+	addr := fs.String("listen-addr", 0, "", "listen address", nil)
+	token := fs.String("token", 0, "", "api token", nil, EnvVars("API_TOKEN"), Required())
+	verbose := fs.Bool("verbose", 'v', false, "verbose output", nil)
+
+	if err := fs.Parse([]string{"--listen-addr", "127.0.0.1", "-v=false"}); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 8080 || !called {
+		t.Errorf("port = %d, callback called = %t; want 8080, true", *port, called)
+	}
+	if *addr != "127.0.0.1" {
+		t.Errorf("listen-addr = %q; command line should win over environment", *addr)
+	}
+	if *token != "secret" {
+		t.Errorf("token = %q; want %q", *token, "secret")
+	}
+	if *verbose {
+		t.Error("verbose should be false; command line should win over environment")
+	}
+	if n := fs.NFlag(); n != 4 {
+		t.Errorf("NFlag() = %d; want 4", n)
+	}
+}
+
+func TestEnvFallbackError(t *testing.T) {
+	setenv(t, "MYAPP_PORT", "eighty")
+
+	fs := NewFlagSet("env test", ContinueOnError, false)
+	fs.EnvPrefix = "MYAPP_"
+	fs.Int("port", 0, 80, "listen port", nil)
+	err := fs.Parse(nil)
+	if err == nil || !strings.Contains(err.Error(), "$MYAPP_PORT") {
+		t.Errorf("expected error naming $MYAPP_PORT; got %v", err)
+	}
+}
+
+func TestEnvFallbackUsage(t *testing.T) {
+	fs := NewFlagSet("env test", ContinueOnError, false)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.EnvPrefix = "MYAPP_"
+	fs.Int("port", 0, 80, "listen port", nil)
+	fs.PrintDefaults()
+	if want := "  --port int\n    \tlisten port [$MYAPP_PORT] (default 80)\n"; buf.String() != want {
+		t.Errorf("got %q want %q", buf.String(), want)
+	}
+}
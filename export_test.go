@@ -4,7 +4,10 @@
 
 package flags
 
-import "os"
+import (
+	"os"
+	"runtime/debug"
+)
 
 // Additional routines compiled into the package only during testing.
 
@@ -18,3 +21,11 @@ func ResetForTesting(usage func()) {
 	CommandLine.Usage = commandLineUsage
 	Usage = usage
 }
+
+// SetBuildInfo replaces the build information read by Version until the
+// returned function is called.
+func SetBuildInfo(info *debug.BuildInfo) (restore func()) {
+	saved := readBuildInfo
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return info, info != nil }
+	return func() { readBuildInfo = saved }
+}
@@ -63,6 +63,8 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // ErrHelp is the error returned if the -help or -h flag is invoked
@@ -71,7 +73,8 @@ var ErrHelp = errors.New("flag: help requested")
 
 // IsIgnorableError
 func IsIgnorableError(err error) bool {
-	return err.Error() == ErrHelp.Error()
+	return err.Error() == ErrHelp.Error() || err.Error() == ErrPrintConfig.Error() ||
+		err.Error() == ErrVersion.Error()
 }
 
 // errParse is returned by Set if a flag's value fails to parse, such as with an invalid integer for Int.
@@ -120,6 +123,32 @@ type Getter interface {
 	Get() interface{}
 }
 
+// newScalarValue returns the Value of this package that stores into p,
+// or nil if p does not point to one of the basic flag types.
+func newScalarValue(p interface{}) Value {
+	switch p := p.(type) {
+	case *bool:
+		return (*boolValue)(p)
+	case *int:
+		return (*intValue)(p)
+	case *int64:
+		return (*int64Value)(p)
+	case *uint:
+		return (*uintValue)(p)
+	case *uint64:
+		return (*uint64Value)(p)
+	case *float64:
+		return (*float64Value)(p)
+	case *string:
+		return (*stringValue)(p)
+	case *time.Duration:
+		return (*durationValue)(p)
+	case *time.Time:
+		return (*timeValue)(p)
+	}
+	return nil
+}
+
 // ErrorHandling defines how FlagSet.Parse behaves if the parse fails.
 type ErrorHandling int
 
@@ -144,9 +173,29 @@ type FlagSet struct {
 	parsed bool
 
 	// adds to original
-	index         int
-	aliasToName   map[rune]string
-	StopImmediate bool // stop immediately if other than flag
+	index           int
+	aliasToName     map[rune]string
+	StopImmediate   bool             // stop immediately if other than flag
+	Negatable       bool             // accept --no-name for boolean flags; set before defining flags
+	EnvPrefix       string           // if set, PREFIX_NAME is an environment fallback for every flag
+	UnknownKeys     UnknownKeyPolicy // how configuration loaders treat keys naming no flag
+	ResponseFiles   bool             // expand @file arguments into the arguments read from file
+	FlagFile        string           // if set, --NAME=file is expanded like @file
+	HelpName        string           // long name of the implicit help flag; "help" if empty, "-" to disable
+	HelpAlias       rune             // alias of the implicit help flag; 'h' if zero, negative to disable
+	VersionTemplate string           // template used by PrintVersion; DefaultVersionTemplate if empty
+
+	constraints []*constraint          // declared constraints
+	config      map[string][]configArg // configuration values applied by Parse
+	sections    map[string]*FlagSet    // INI sections loaded into child flag sets
+	dotenv      *Flag                  // flag naming a .env file loaded by Parse
+	printConfig *Flag                  // flag requesting PrintConfig
+	version     *Flag                  // flag requesting PrintVersion
+	versionText string                 // version given to VersionFlag
+	sources     []source               // sources consulted by Parse, in order
+	parent      *FlagSet               // flag set whose flags are inherited
+	children    []*FlagSet             // flag sets inheriting the flags
+	showHidden  bool                   // usage messages include hidden flags
 
 	actual        map[string]*Flag
 	formal        map[string]*Flag
@@ -168,9 +217,46 @@ type Flag struct {
 	Value    Value  // value as set
 	DefValue string // default value (as text); for usage message
 
+	// NoOptDefVal, if not empty, is the value used when the flag is given
+	// without "=value" text, as in --color for --color=auto. Such a flag
+	// never consumes the next argument.
+	NoOptDefVal string
+
+	Required bool     // Parse fails if the flag is not set
+	EnvVars  []string // environment variables consulted if the flag is not set
+	Hidden   bool     // omitted from usage messages unless --help-all is given
+	Secret   bool     // value masked by PrintConfig
+
+	Origin   Origin // where the value came from
+	RawValue string // argument of the last call to Value.Set; empty for the default
+	Location string // where the value was found, such as a file position or $VAR
+
 	fn Callback
 }
 
+// An Option sets a property of a Flag when it is defined. Options are
+// accepted by Var and by every typed flag constructor.
+type Option func(*Flag)
+
+// EnvVars sets the environment variables from which Parse takes the value
+// of the flag if it is not given on the command line. The first variable
+// that is set is used.
+func EnvVars(names ...string) Option {
+	return func(flag *Flag) { flag.EnvVars = append(flag.EnvVars, names...) }
+}
+
+// NoOptDefVal sets the value the flag takes when it is given without
+// "=value" text, as in --color for --color=auto. See Flag.NoOptDefVal.
+func NoOptDefVal(value string) Option {
+	return func(flag *Flag) { flag.NoOptDefVal = value }
+}
+
+// Required marks the flag as required. After the arguments are parsed,
+// Parse reports every required flag that was not set in a single error.
+func Required() Option {
+	return func(flag *Flag) { flag.Required = true }
+}
+
 // Output returns the destination for usage and error messages. os.Stderr is returned if
 // output was not set or was set to nil.
 func (f *FlagSet) Output() io.Writer {
@@ -217,6 +303,7 @@ func (f *FlagSet) Set(name, value string) error {
 	if err != nil {
 		return err
 	}
+	flag.record(OriginSet, value, "")
 	if f.actual == nil {
 		f.actual = make(map[string]*Flag)
 	}
@@ -269,10 +356,14 @@ func Args() []string { return CommandLine.args }
 // typically holds a user-defined implementation of Value. For instance, the
 // caller could create a flag that turns a comma-separated string into a slice
 // of strings by giving the slice the methods of Value; in particular, Set would
-// decompose the comma-separated string into the slice.
-func (f *FlagSet) Var(value Value, name string, alias rune, usage string, fn Callback) {
+// decompose the comma-separated string into the slice. Options such as
+// Required set further properties of the flag.
+func (f *FlagSet) Var(value Value, name string, alias rune, usage string, fn Callback, opts ...Option) {
 	// Remember the default value as a string; it won't change.
 	flag := &Flag{Name: name, Alias: alias, Usage: usage, Value: value, DefValue: value.String(), fn: fn}
+	for _, opt := range opts {
+		opt(flag)
+	}
 
 	_, alreadythere := f.formal[name]
 	if alreadythere {
@@ -305,6 +396,10 @@ func (f *FlagSet) Var(value Value, name string, alias rune, usage string, fn Cal
 		panic(msg) // Happens only if flags are declared with identical names
 	}
 
+	f.checkInherited(name, alias)
+	f.checkDescendants(flag)
+	f.checkNegation(flag)
+
 	if f.formal == nil {
 		f.formal = make(map[string]*Flag)
 	}
@@ -319,14 +414,47 @@ func (f *FlagSet) Var(value Value, name string, alias rune, usage string, fn Cal
 	}
 }
 
+// negationCollision returns a message if flag of f and a flag defined by
+// f or inherited would both be named by the same --no-name argument, or
+// "" otherwise.
+func (f *FlagSet) negationCollision(flag *Flag) string {
+	var name, negated string
+	if f.Negatable && isNegatable(flag.Value) {
+		if other, _ := f.lookupFlag("no-" + flag.Name); other != nil {
+			name, negated = other.Name, flag.Name
+		}
+	}
+	if strings.HasPrefix(flag.Name, "no-") {
+		if other, owner := f.lookupFlag(flag.Name[3:]); other != nil && owner.Negatable && isNegatable(other.Value) {
+			name, negated = flag.Name, other.Name
+		}
+	}
+	if name == "" {
+		return ""
+	}
+	if f.name == "" {
+		return fmt.Sprintf("flag %s collides with the negation of %s", name, negated)
+	}
+	return fmt.Sprintf("%s flag %s collides with the negation of %s", f.name, name, negated)
+}
+
+// checkNegation panics if negationCollision reports a collision for flag.
+func (f *FlagSet) checkNegation(flag *Flag) {
+	if msg := f.negationCollision(flag); msg != "" {
+		fmt.Fprintln(f.Output(), msg)
+		panic(msg) // Happens only if flags are declared with colliding names
+	}
+}
+
 // Var defines a flag with the specified name and usage string. The type and
 // value of the flag are represented by the first argument, of type Value, which
 // typically holds a user-defined implementation of Value. For instance, the
 // caller could create a flag that turns a comma-separated string into a slice
 // of strings by giving the slice the methods of Value; in particular, Set would
-// decompose the comma-separated string into the slice.
-func Var(value Value, name string, alias rune, usage string, fn Callback) {
-	CommandLine.Var(value, name, alias, usage, fn)
+// decompose the comma-separated string into the slice. Options such as
+// Required set further properties of the flag.
+func Var(value Value, name string, alias rune, usage string, fn Callback, opts ...Option) {
+	CommandLine.Var(value, name, alias, usage, fn, opts...)
 }
 
 // CommandLine is the default set of command-line flags, parsed from os.Args.
@@ -568,9 +568,21 @@ func TestShortFlag(t *testing.T) {
 			e: Expect{false, "value", 100},
 		},
 		{
-			a: []string{"-sib", "value", "100"},
+			a: []string{"-bs", "value", "-i100"},
 			e: Expect{true, "value", 100},
 		},
+		{
+			a: []string{"-bsvalue", "-bi=100"},
+			e: Expect{true, "value", 100},
+		},
+		{
+			a: []string{"-sb", "-i", "-100"},
+			e: Expect{false, "b", -100},
+		},
+		{
+			a: []string{"-bb=false", "-s=a=b"},
+			e: Expect{false, "a=b", 0},
+		},
 	}
 
 	var flags *FlagSet
@@ -614,7 +626,7 @@ func TestRemainderArguments(t *testing.T) {
 			r: []string{},
 		},
 		{
-			a: []string{"A", "B", "C", "-sib", "value", "100"},
+			a: []string{"A", "B", "C", "-bs", "value", "-i100"},
 			r: []string{"A", "B", "C"},
 		},
 		{
@@ -646,3 +658,137 @@ func TestRemainderArguments(t *testing.T) {
 		}
 	}
 }
+
+func TestNegatableFlag(t *testing.T) {
+	fs := NewFlagSet("negatable test", ContinueOnError, false)
+	fs.SetOutput(ioutil.Discard)
+	fs.Negatable = true
+	color := fs.Bool("color", 'c', true, "colorize output", nil)
+	fs.String("name", 0, "", "a name", nil)
+
+	if err := fs.Parse([]string{"--no-color"}); err != nil {
+		t.Fatal(err)
+	}
+	if *color {
+		t.Error("color flag should be false after --no-color")
+	}
+	if err := fs.Parse([]string{"--no-color", "--color"}); err != nil {
+		t.Fatal(err)
+	}
+	if !*color {
+		t.Error("color flag should be true after --color")
+	}
+	for _, arg := range []string{"--no-color=true", "--no-name"} {
+		if err := fs.Parse([]string{arg}); err == nil {
+			t.Errorf("Parse(%q) succeeded; expected error", arg)
+		}
+	}
+
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	if want := "  -c, --[no-]color\n"; !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("PrintDefaults() = %q; want prefix %q", buf.String(), want)
+	}
+}
+
+func TestNegatableCount(t *testing.T) {
+	fs := NewFlagSet("negatable test", ContinueOnError, false)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.Negatable = true
+	fs.Count("verbose", 'v', 0, "verbosity", nil)
+	fs.Bool("no-verbose", 0, false, "a flag of its own", nil)
+
+	fs.PrintDefaults()
+	if want := "  -v, --verbose\n"; !strings.HasPrefix(buf.String(), "  --[no-]no-verbose\n") || !strings.Contains(buf.String(), want) {
+		t.Errorf("usage %q; want --verbose without [no-]", buf.String())
+	}
+	if err := fs.Parse([]string{"-vv", "--no-verbose"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := fs.Lookup("verbose").Value.String(); got != "2" {
+		t.Errorf("verbose = %s; --no-verbose should not reset the counter", got)
+	}
+}
+
+func TestNegatableFlagCollision(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for colliding negation")
+		}
+	}()
+	fs := NewFlagSet("negatable test", ContinueOnError, false)
+	fs.SetOutput(ioutil.Discard)
+	fs.Negatable = true
+	fs.Bool("color", 0, true, "colorize output", nil)
+	fs.Bool("no-color", 0, false, "disable colors", nil)
+}
+
+func TestNoOptDefVal(t *testing.T) {
+	data := []struct {
+		a []string
+		e string
+		r []string // remainder argument
+	}{
+		{a: []string{"--color"}, e: "auto"},
+		{a: []string{"--color=always"}, e: "always"},
+		{a: []string{"--color", "always"}, e: "auto", r: []string{"always"}},
+		{a: []string{"-c", "always"}, e: "auto", r: []string{"always"}},
+		{a: []string{"-c=never"}, e: "never"},
+	}
+
+	for _, v := range data {
+		fs := NewFlagSet("optional test", ContinueOnError, false)
+		color := fs.String("color", 'c', "never", "colorize output", nil, NoOptDefVal("auto"))
+
+		if err := fs.Parse(v.a); err != nil {
+			t.Errorf("Parse(%q): %v", v.a, err)
+			continue
+		}
+		if *color != v.e {
+			t.Errorf("Parse(%q): color = %q; want %q", v.a, *color, v.e)
+		}
+		if len(fs.Args()) != len(v.r) {
+			t.Errorf("Parse(%q): args = %q; want %q", v.a, fs.Args(), v.r)
+		}
+	}
+
+	fs := NewFlagSet("optional test", ContinueOnError, false)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.String("color", 0, "", "colorize output `when`", nil, NoOptDefVal("auto"))
+	fs.PrintDefaults()
+	if want := "  --color[=when]\n    \tcolorize output when\n"; buf.String() != want {
+		t.Errorf("got %q want %q", buf.String(), want)
+	}
+}
+
+func TestRequiredFlags(t *testing.T) {
+	fs := NewFlagSet("required test", ContinueOnError, false)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.String("token", 't', "", "api token", nil, Required())
+	fs.Int("port", 0, 0, "listen port", nil, Required())
+	fs.Bool("verbose", 'v', false, "verbose output", nil)
+
+	err := fs.Parse([]string{"-v"})
+	if err == nil || err.Error() != "required flags not set: --port, --token" {
+		t.Errorf("expected missing flags error; got %v", err)
+	}
+	err = fs.Parse([]string{"--port", "80"})
+	if err == nil || err.Error() != "required flag not set: --token" {
+		t.Errorf("expected missing flag error; got %v", err)
+	}
+	if err := fs.Parse([]string{"-tsecret"}); err != nil {
+		t.Errorf("expected no error; got %v", err)
+	}
+	if !fs.Lookup("token").Required || fs.Lookup("verbose").Required {
+		t.Error("Required is not exposed on Flag")
+	}
+
+	fs.PrintDefaults()
+	if want := "  -t, --token string\n    \tapi token (required)\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("PrintDefaults() = %q; want it to contain %q", buf.String(), want)
+	}
+}
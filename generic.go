@@ -0,0 +1,128 @@
+package flags
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// A Parser converts between values of type T and their text on the
+// command line. Registering a Parser with Register lets Add define flags
+// of type T.
+type Parser[T any] struct {
+	Name   string                  // placeholder shown by UnquoteUsage, such as "ip"
+	Parse  func(string) (T, error) // parses an argument
+	Format func(T) string          // formats a value, such as the default
+}
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = make(map[reflect.Type]interface{}) // Parser[T] by T
+)
+
+// Register makes p the Parser used by Add for flags of type T, replacing
+// any earlier one. The types with a flag constructor in this package,
+// such as int and time.Duration, always use their built-in values, and
+// Register panics for them.
+func Register[T any](p Parser[T]) {
+	var zero T
+	if newScalarValue(&zero) != nil {
+		panic(fmt.Sprintf("flag: cannot register a parser for built-in type %T", zero))
+	}
+	if p.Parse == nil || p.Format == nil {
+		panic(fmt.Sprintf("flag: parser for %T lacks Parse or Format", zero))
+	}
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[reflect.TypeOf((*T)(nil)).Elem()] = p
+}
+
+func lookupParser[T any]() (Parser[T], bool) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	p, ok := parsers[reflect.TypeOf((*T)(nil)).Elem()].(Parser[T])
+	return p, ok
+}
+
+// -- generic Value
+type genericValue[T any] struct {
+	p      *T
+	parser Parser[T]
+}
+
+func (g *genericValue[T]) Set(s string) error {
+	v, err := g.parser.Parse(s)
+	if err != nil {
+		return err
+	}
+	*g.p = v
+	return nil
+}
+
+func (g *genericValue[T]) Get() interface{} { return *g.p }
+
+func (g *genericValue[T]) String() string {
+	if g.p == nil {
+		// The zero Value built by isZeroValue.
+		var zero T
+		if p, ok := lookupParser[T](); ok {
+			return p.Format(zero)
+		}
+		return ""
+	}
+	return g.parser.Format(*g.p)
+}
+
+// typeName returns the placeholder shown by UnquoteUsage.
+func (g *genericValue[T]) typeName() string {
+	if g.parser.Name == "" {
+		return "value"
+	}
+	return g.parser.Name
+}
+
+// newValue returns the Value that stores into p: the built-in value of
+// this package for T, or else one using the Parser registered for T, or
+// nil if there is neither.
+func newValue[T any](p *T) Value {
+	if v := newScalarValue(p); v != nil {
+		return v
+	}
+	if parser, ok := lookupParser[T](); ok {
+		return &genericValue[T]{p: p, parser: parser}
+	}
+	return nil
+}
+
+// define defines a flag of type T storing into p with the given default.
+func define[T any](f *FlagSet, p *T, name string, alias rune, value T, usage string, fn Callback, opts []Option) {
+	v := newValue(p)
+	if v == nil {
+		msg := fmt.Sprintf("flag: no parser registered for type %T", value)
+		fmt.Fprintln(f.Output(), msg)
+		panic(msg) // Happens only if Register was not called for T
+	}
+	*p = value
+	f.Var(v, name, alias, usage, fn, opts...)
+}
+
+// Add defines a flag of type T with specified name, alias, default value,
+// and usage string. The argument p points to a T variable in which to
+// store the value of the flag. T is any type with a flag constructor in
+// this package, such as int, string or time.Duration, or a type with a
+// Parser given to Register; Add panics for other types. If fn is not nil,
+// it is called with the new value each time the flag is set.
+func Add[T any](f *FlagSet, p *T, name string, alias rune, value T, usage string, fn func(T) error, opts ...Option) {
+	var callback Callback
+	if fn != nil {
+		callback = func(g Getter) error { return fn(g.Get().(T)) }
+	}
+	define(f, p, name, alias, value, usage, callback, opts)
+}
+
+// New is like Add but allocates the variable and returns its address.
+func New[T any](f *FlagSet, name string, alias rune, value T, usage string, fn func(T) error, opts ...Option) *T {
+	p := new(T)
+	Add(f, p, name, alias, value, usage, fn, opts...)
+	return p
+}
@@ -0,0 +1,91 @@
+package flags_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/saihon/flags"
+)
+
+func init() {
+	Register(Parser[net.IP]{
+		Name: "ip",
+		Parse: func(s string) (net.IP, error) {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: s}
+			}
+			return ip, nil
+		},
+		Format: func(ip net.IP) string {
+			if ip == nil {
+				return ""
+			}
+			return ip.String()
+		},
+	})
+}
+
+func TestAdd(t *testing.T) {
+	fs := NewFlagSet("generic test", ContinueOnError, false)
+	var port int
+	var seen []int
+	Add(fs, &port, "port", 'p', 80, "listen port", func(v int) error {
+		seen = append(seen, v)
+		return nil
+	})
+	timeout := New(fs, "timeout", 0, 5*time.Second, "timeout", nil)
+	ip := New(fs, "ip", 0, net.IPv4(127, 0, 0, 1), "bind address", nil)
+
+	if err := fs.Parse([]string{"-p", "8080", "--ip", "10.0.0.1", "--port=9090"}); err != nil {
+		t.Fatal(err)
+	}
+	if port != 9090 || *timeout != 5*time.Second || !ip.Equal(net.IPv4(10, 0, 0, 1)) {
+		t.Errorf("got port=%d timeout=%v ip=%v", port, *timeout, *ip)
+	}
+	if len(seen) != 2 || seen[0] != 8080 || seen[1] != 9090 {
+		t.Errorf("callback saw %v; want [8080 9090]", seen)
+	}
+
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	if err := fs.Parse([]string{"--ip", "nowhere"}); err == nil {
+		t.Error("expected error for invalid IP")
+	}
+}
+
+func TestAddUsage(t *testing.T) {
+	fs := NewFlagSet("generic test", ContinueOnError, false)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	New(fs, "ip", 0, net.IPv4(127, 0, 0, 1), "bind address", nil)
+	New[net.IP](fs, "peer", 0, nil, "peer address", nil)
+	fs.PrintDefaults()
+	want := "  --ip ip\n    \tbind address (default 127.0.0.1)\n" +
+		"  --peer ip\n    \tpeer address\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestAddUnregistered(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Add of an unregistered type did not panic")
+		}
+	}()
+	fs := NewFlagSet("generic test", ContinueOnError, false)
+	fs.SetOutput(&bytes.Buffer{})
+	New(fs, "complex", 0, complex(1, 2), "", nil)
+}
+
+func TestRegisterBuiltin(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register of a built-in type did not panic")
+		}
+	}()
+	Register(Parser[int]{})
+}
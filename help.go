@@ -0,0 +1,61 @@
+package flags
+
+import "fmt"
+
+// Hidden hides the flag from usage messages, unless help is requested
+// with --help-all. The flag is accepted as usual.
+func Hidden() Option {
+	return func(flag *Flag) { flag.Hidden = true }
+}
+
+// helpName returns the long name of the implicit help flag, or "" if it
+// is disabled.
+func (f *FlagSet) helpName() string {
+	switch f.HelpName {
+	case "":
+		return "help"
+	case "-":
+		return ""
+	}
+	return f.HelpName
+}
+
+// helpAlias returns the alias of the implicit help flag, or 0 if it is
+// disabled.
+func (f *FlagSet) helpAlias() rune {
+	switch {
+	case f.HelpAlias == 0:
+		return 'h'
+	case f.HelpAlias < 0:
+		return 0
+	}
+	return f.HelpAlias
+}
+
+// help handles the implicit help flags, which apply unless a flag of the
+// same name is defined: --help and -h print the usage message,
+// --help=name describes the named flag, and --help-all prints the usage
+// message including hidden flags. It reports whether name, given on the
+// command line with value if hasValue, requested help.
+func (f *FlagSet) help(name, value string, hasValue bool) (bool, error) {
+	help := f.helpName()
+	switch {
+	case help == "":
+		return false, nil
+	case name == help && hasValue:
+		flag, owner := f.lookupFlag(value)
+		if flag == nil {
+			return true, f.failf("no help for undefined flag: --%s", value)
+		}
+		fmt.Fprintln(f.Output(), owner.flagUsage(flag))
+	case name == help:
+		f.usage()
+	case name == help+"-all" && !hasValue:
+		f.showHidden = true
+		f.usage()
+		f.showHidden = false
+	default:
+		return false, nil
+	}
+	return true, ErrHelp
+}
@@ -0,0 +1,116 @@
+package flags_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+func newHelpFlagSet(buf *bytes.Buffer) *FlagSet {
+	fs := NewFlagSet("help test", ContinueOnError, false)
+	fs.SetOutput(buf)
+	fs.Usage = fs.PrintDefaults
+	fs.Int("port", 'p', 80, "listen `port`", nil, EnvVars("PORT"), Required())
+	fs.Bool("debug-internals", 0, false, "dump internal state", nil, Hidden())
+	return fs
+}
+
+func TestHelpFlags(t *testing.T) {
+	data := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"-h"}, want: "  -p, --port port\n    \tlisten port [$PORT] (default 80) (required)\n"},
+		{args: []string{"--help"}, want: "  -p, --port port\n    \tlisten port [$PORT] (default 80) (required)\n"},
+		{args: []string{"--help=port"}, want: "  -p, --port port\n    \tlisten port [$PORT] (default 80) (required)\n"},
+		{args: []string{"--help=debug-internals"}, want: "  --debug-internals\n    \tdump internal state\n"},
+		{args: []string{"--help-all"}, want: "  --debug-internals\n    \tdump internal state\n" +
+			"  -p, --port port\n    \tlisten port [$PORT] (default 80) (required)\n"},
+	}
+
+	for _, v := range data {
+		var buf bytes.Buffer
+		fs := newHelpFlagSet(&buf)
+		if err := fs.Parse(v.args); err != ErrHelp {
+			t.Errorf("%q: got %v; want ErrHelp", v.args, err)
+		}
+		if got := buf.String(); got != v.want {
+			t.Errorf("%q: got %q want %q", v.args, got, v.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	fs := newHelpFlagSet(&buf)
+	if err := fs.Parse([]string{"--help=nope"}); err == nil || err == ErrHelp {
+		t.Errorf("--help=nope: got %v; want an error", err)
+	}
+}
+
+func TestHelpFlagRenamed(t *testing.T) {
+	var buf bytes.Buffer
+	fs := newHelpFlagSet(&buf)
+	fs.HelpAlias = '?'
+	fs.HelpName = "usage"
+	host := fs.String("host", 'h', "", "host", nil)
+
+	if err := fs.Parse([]string{"-h", "example.com", "-p1"}); err != nil {
+		t.Fatal(err)
+	}
+	if *host != "example.com" {
+		t.Errorf("host = %q", *host)
+	}
+	for _, arg := range []string{"-?", "--usage"} {
+		if err := fs.Parse([]string{arg}); err != ErrHelp {
+			t.Errorf("%s: got %v; want ErrHelp", arg, err)
+		}
+	}
+
+	fs.HelpName = "-"
+	fs.HelpAlias = -1
+	for _, arg := range []string{"-?", "--help", "--usage"} {
+		if err := fs.Parse([]string{arg}); err == nil || err == ErrHelp {
+			t.Errorf("%s: got %v; want an undefined flag error", arg, err)
+		}
+	}
+}
+
+func TestHelpCommand(t *testing.T) {
+	var ran string
+	var rest []string
+	root, _, _ := newTestTree(&ran, &rest)
+	var buf bytes.Buffer
+	root.Flags.SetOutput(&buf)
+	for _, c := range root.Commands() {
+		c.Flags.SetOutput(&buf)
+		for _, sub := range c.Commands() {
+			sub.Flags.SetOutput(&buf)
+		}
+	}
+
+	if err := root.Execute([]string{"help", "remote", "add"}); err != ErrHelp {
+		t.Fatalf("got %v; want ErrHelp", err)
+	}
+	if got := buf.String(); !strings.HasPrefix(got, "\nUsage: prog remote add [flags]\n\nadd a remote\n") {
+		t.Errorf("got %q", got)
+	}
+
+	buf.Reset()
+	if err := root.Execute([]string{"help"}); err != ErrHelp || !strings.HasPrefix(buf.String(), "\nUsage: prog [flags] <command>\n") {
+		t.Errorf("got %v, %q", err, buf.String())
+	}
+
+	err := root.Execute([]string{"help", "remote", "rename"})
+	if _, ok := err.(*CommandError); !ok {
+		t.Errorf("got %v; want *CommandError", err)
+	}
+
+	root.DisableHelpCommand = true
+	if _, ok := root.Execute([]string{"help"}).(*CommandError); !ok {
+		t.Errorf("help should be an unknown command when disabled")
+	}
+	if ran != "" {
+		t.Errorf("ran %q", ran)
+	}
+}
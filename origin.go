@@ -0,0 +1,111 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+)
+
+// Origin identifies where the value of a flag came from. A value from a
+// later origin in the list of constants takes precedence over one from an
+// earlier origin, in the order described at Parse.
+type Origin int
+
+// These constants identify the origin of the value of a flag.
+const (
+	OriginDefault     Origin = iota // The flag has its default value.
+	OriginConfig                    // The value is from a configuration file or reader, such as LoadJSON.
+	OriginSource                    // The value is from a Source added with AddSource.
+	OriginEnv                       // The value is from an environment variable.
+	OriginCommandLine               // The value is from the arguments given to Parse.
+	OriginSet                       // The value was set by the program with Set.
+)
+
+var originNames = []string{
+	OriginDefault:     "default",
+	OriginConfig:      "config",
+	OriginSource:      "source",
+	OriginEnv:         "env",
+	OriginCommandLine: "command line",
+	OriginSet:         "set",
+}
+
+func (o Origin) String() string {
+	if o < 0 || int(o) >= len(originNames) {
+		return fmt.Sprintf("Origin(%d)", int(o))
+	}
+	return originNames[o]
+}
+
+// ErrPrintConfig is the error returned by Parse after it printed the
+// configuration because the flag defined by PrintConfigFlag was given.
+var ErrPrintConfig = errors.New("flag: configuration printed")
+
+// record notes that the value of flag was set to raw from origin, found
+// at location.
+func (flag *Flag) record(origin Origin, raw, location string) {
+	flag.Origin = origin
+	flag.RawValue = raw
+	flag.Location = location
+}
+
+// Secret marks the flag as holding a secret, such as a password or a
+// token, whose value PrintConfig replaces with asterisks.
+func Secret() Option {
+	return func(flag *Flag) { flag.Secret = true }
+}
+
+// isTrue reports whether flag is a boolean flag set to true.
+func isTrue(flag *Flag) bool {
+	g, ok := flag.Value.(Getter)
+	if !ok {
+		return false
+	}
+	b, _ := g.Get().(bool)
+	return b
+}
+
+// PrintConfigFlag defines a boolean flag with specified name, alias and
+// usage string. If it is set to true, Parse prints the configuration with
+// PrintConfig once every source has been applied, and returns
+// ErrPrintConfig; with ExitOnError the program exits with status 0.
+func (f *FlagSet) PrintConfigFlag(name string, alias rune, usage string) {
+	f.Bool(name, alias, false, usage, nil)
+	f.printConfig = f.formal[name]
+}
+
+// PrintConfigFlag defines a boolean command-line flag that prints the
+// configuration. See FlagSet.PrintConfigFlag.
+func PrintConfigFlag(name string, alias rune, usage string) {
+	CommandLine.PrintConfigFlag(name, alias, usage)
+}
+
+// PrintConfig prints, to standard error unless configured otherwise, the
+// effective value of every flag in the set together with its origin and
+// the location within that origin, such as a file position or the name of
+// an environment variable. Values are printed verbatim, except those of
+// flags marked with the Secret option.
+func (f *FlagSet) PrintConfig() {
+	w := tabwriter.NewWriter(f.Output(), 0, 4, 2, ' ', 0)
+	f.VisitAll(func(flag *Flag) {
+		if flag == f.printConfig {
+			return
+		}
+		value := flag.Value.String()
+		if flag.Secret {
+			value = "********"
+		}
+		fmt.Fprintf(w, "--%s=%s\t# %s", flag.Name, value, flag.Origin)
+		if flag.Location != "" {
+			fmt.Fprintf(w, " (%s)", flag.Location)
+		}
+		fmt.Fprintln(w)
+	})
+	w.Flush()
+}
+
+// PrintConfig prints the effective value and origin of every command-line
+// flag. See FlagSet.PrintConfig.
+func PrintConfig() {
+	CommandLine.PrintConfig()
+}
@@ -0,0 +1,75 @@
+package flags_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+func TestOrigin(t *testing.T) {
+	setenv(t, "ORIGIN_TEST_HOST", "env.example.com")
+	fs := NewFlagSet("origin test", ContinueOnError, false)
+	fs.Int("port", 'p', 80, "", nil)
+	fs.String("host", 0, "localhost", "", nil, EnvVars("ORIGIN_TEST_HOST"))
+	fs.String("user", 0, "nobody", "", nil)
+	fs.Bool("verbose", 0, false, "", nil)
+	fs.String("name", 0, "", "", nil)
+
+	if err := fs.LoadJSON(strings.NewReader(`{"user": "gopher", "host": "file"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-p8080"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Set("name", "program"); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []struct {
+		name     string
+		origin   Origin
+		raw      string
+		location string
+	}{
+		{"port", OriginCommandLine, "8080", ""},
+		{"host", OriginEnv, "env.example.com", "$ORIGIN_TEST_HOST"},
+		{"user", OriginConfig, "gopher", "$.user"},
+		{"verbose", OriginDefault, "", ""},
+		{"name", OriginSet, "program", ""},
+	}
+	for _, v := range data {
+		flag := fs.Lookup(v.name)
+		if flag.Origin != v.origin || flag.RawValue != v.raw || flag.Location != v.location {
+			t.Errorf("%s: got %v %q %q; want %v %q %q", v.name,
+				flag.Origin, flag.RawValue, flag.Location, v.origin, v.raw, v.location)
+		}
+	}
+}
+
+func TestPrintConfig(t *testing.T) {
+	fs := NewFlagSet("origin test", ContinueOnError, false)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.Int("port", 0, 80, "", nil)
+	fs.String("host", 0, "localhost", "", nil)
+	fs.String("token", 0, "", "", nil, Secret())
+	fs.PrintConfigFlag("print-config", 0, "print the configuration and exit")
+
+	err := fs.Parse([]string{"--port=8080", "--token=s3cret", "--print-config"})
+	if err != ErrPrintConfig || !IsIgnorableError(err) {
+		t.Fatalf("Parse() = %v; want ErrPrintConfig", err)
+	}
+	want := "--host=localhost  # default\n" +
+		"--port=8080       # command line\n" +
+		"--token=********  # command line\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	buf.Reset()
+	if err := fs.Parse([]string{"--print-config=false"}); err != nil || buf.Len() != 0 {
+		t.Errorf("--print-config=false: got %v, output %q", err, buf.String())
+	}
+}
@@ -7,12 +7,19 @@ package flags
 import (
 	"fmt"
 	"os"
+	"strings"
+	"unicode/utf8"
 )
 
 // failf prints to standard error a formatted error and usage message and
 // returns the error.
 func (f *FlagSet) failf(format string, a ...interface{}) error {
-	err := fmt.Errorf(format, a...)
+	return f.fail(fmt.Errorf(format, a...))
+}
+
+// fail prints err to standard error unless errors are returned to the
+// caller, and returns it.
+func (f *FlagSet) fail(err error) error {
 	if f.errorHandling != ContinueOnError {
 		fmt.Fprintln(f.Output(), err)
 	}
@@ -37,34 +44,38 @@ func (f *FlagSet) cut() string {
 }
 
 func (f *FlagSet) setValue(flag *Flag, value string, hasValue bool) error {
-	var err error
-	// boolean value is inverted unless a value is explicitly specified with "="
-	if b, ok := flag.Value.(boolFlag); ok && b.IsBoolFlag() {
-		if hasValue {
-			if err = flag.Value.Set(value); err != nil && err != ErrHelp {
-				err = f.failf("invalid boolean value %q for --%s: %v", value, flag.Name, err)
-			}
-		} else {
-			if err = flag.Value.Set("true"); err != nil && err != ErrHelp {
-				err = f.failf("invalid boolean flag %s: %v", flag.Name, err)
-			}
-		}
-	} else if hasValue {
-		if err = flag.Value.Set(value); err != nil && err != ErrHelp {
+	switch {
+	case hasValue:
+	case flag.NoOptDefVal != "":
+		value = flag.NoOptDefVal
+	case isBoolFlag(flag.Value):
+		// boolean value is inverted unless a value is explicitly specified with "="
+		value = "true"
+	case f.index < len(f.args):
+		value = f.cut()
+	default:
+		return f.failf("flag needs an argument: --%s", flag.Name)
+	}
+
+	err := flag.Value.Set(value)
+	if err != nil && err != ErrHelp {
+		if isBoolFlag(flag.Value) {
 			err = f.failf("invalid boolean value %q for --%s: %v", value, flag.Name, err)
-		}
-	} else if f.index < len(f.args) {
-		if err = flag.Value.Set(f.cut()); err != nil && err != ErrHelp {
+		} else {
 			err = f.failf("invalid value %q for flag --%s: %v", value, flag.Name, err)
 		}
-	} else {
-		err = f.failf("flag needs an argument: --%s", flag.Name)
 	}
 
 	if err != nil {
 		return err
 	}
+	flag.record(OriginCommandLine, value, "")
+
+	return f.callback(flag)
+}
 
+// callback calls the callback function of flag, if any, after its value was set.
+func (f *FlagSet) callback(flag *Flag) error {
 	if flag.fn != nil {
 		g, ok := flag.Value.(Getter)
 		if !ok {
@@ -85,6 +96,10 @@ func (f *FlagSet) parseOne() (bool, error) {
 		return false, nil
 	}
 
+	if expanded, err := f.expandResponseFile(); expanded {
+		return err == nil && f.index < len(f.args), err
+	}
+
 	s := f.args[f.index]
 
 	if len(s) > 1 && s[0] == '-' {
@@ -115,14 +130,23 @@ func (f *FlagSet) parseOne() (bool, error) {
 			}
 		}
 
-		m := f.formal
 		switch numMinuses {
 		case 2:
-			flag, alreadythere := m[name] // BUG
-			if !alreadythere {
-				if name == "help" { // special case for nice help message.
-					f.usage()
-					return false, ErrHelp
+			flag, owner := f.lookupFlag(name)
+			if flag == nil && strings.HasPrefix(name, "no-") {
+				if flag, owner = f.lookupFlag(name[3:]); flag != nil && owner.Negatable && isNegatable(flag.Value) {
+					if hasValue {
+						return false, f.failf("negated flag does not take a value: --%s", name)
+					}
+					value, hasValue = "false", true
+					name = flag.Name
+				} else {
+					flag = nil
+				}
+			}
+			if flag == nil {
+				if help, err := f.help(name, value, hasValue); help { // special case for nice help message.
+					return false, err
 				}
 				return false, f.failf("flag provided but not defined: --%s", name)
 			}
@@ -130,17 +154,19 @@ func (f *FlagSet) parseOne() (bool, error) {
 			if err := f.setValue(flag, value, hasValue); err != nil {
 				return false, err
 			}
-
-			if f.actual == nil {
-				f.actual = make(map[string]*Flag)
+			owner.setActual(flag)
+			if err := owner.checkVersion(flag); err != nil {
+				return false, err
 			}
-			f.actual[name] = flag
 
 		case 1:
-			for _, v := range name {
-				longname, alreadythere := f.aliasToName[v]
-				if !alreadythere {
-					if v == 'h' { // special case for nice help message.
+			// As with getopt, a flag that takes a value ends the cluster:
+			// the rest of the argument is its value, as in -ofile or -vofile.
+			// Otherwise the value after "=" belongs to the last flag only.
+			for i, v := range name {
+				flag, owner := f.lookupAlias(v)
+				if flag == nil {
+					if v == f.helpAlias() { // special case for nice help message.
 						f.usage()
 						return false, ErrHelp
 					}
@@ -148,17 +174,28 @@ func (f *FlagSet) parseOne() (bool, error) {
 					return false, f.failf("flag provided but not defined: -%c", v)
 				}
 
-				flag, alreadythere := m[longname]
-				if alreadythere {
-					if err := f.setValue(flag, value, hasValue); err != nil {
-						return false, err
-					}
+				rest := s[1+i+utf8.RuneLen(v):]
+				implicit := isBoolFlag(flag.Value) && flag.NoOptDefVal == ""
+				var err error
+				switch {
+				case rest == "" || rest[0] == '=':
+					err = f.setValue(flag, value, hasValue)
+				case implicit:
+					err = f.setValue(flag, "", false)
+				default:
+					err = f.setValue(flag, rest, true)
+				}
+				if err != nil {
+					return false, err
+				}
+				owner.setActual(flag)
+				if err := owner.checkVersion(flag); err != nil {
+					return false, err
 				}
 
-				if f.actual == nil {
-					f.actual = make(map[string]*Flag)
+				if !implicit {
+					break
 				}
-				f.actual[longname] = flag
 			}
 		}
 
@@ -180,7 +217,27 @@ func (f *FlagSet) parseOne() (bool, error) {
 // include the command name. Must be called after all flags in the FlagSet
 // are defined and before flags are accessed by the program.
 // The return value will be ErrHelp if -help or -h were set but not defined.
+//
+// A flag not given on the command line takes its value from the first of
+// these layers that holds one, in order of precedence:
+//
+//   - the environment variables named with EnvVars or derived from EnvPrefix,
+//   - the sources added with AddSource, in the order added,
+//   - the .env file named by a DotenvFile flag, loaded by Parse,
+//   - the configuration loaded with LoadJSON, LoadYAML, LoadTOML, LoadINI,
+//     LoadDotenv or their File variants, where a later load replaces the
+//     values of an earlier one for the same flag,
+//
+// or else keeps its default. Set, called after Parse, overrides them all.
 func (f *FlagSet) Parse(arguments []string) error {
+	if err := f.parseArgs(arguments); err != nil {
+		return err
+	}
+	return f.finish()
+}
+
+// parseArgs parses the flags in arguments, the first step of Parse.
+func (f *FlagSet) parseArgs(arguments []string) error {
 	f.parsed = true
 	f.index = 0
 	f.args = arguments
@@ -190,20 +247,74 @@ func (f *FlagSet) Parse(arguments []string) error {
 			continue
 		}
 		if err == nil {
-			break
-		}
-		switch f.errorHandling {
-		case ContinueOnError:
-			return err
-		case ExitOnError:
-			os.Exit(2)
-		case PanicOnError:
-			panic(err)
+			return nil
 		}
+		return f.handleError(err)
+	}
+}
+
+// finish sets the flags not given on the command line from the other
+// layers and checks the required flags and constraints, the second step
+// of Parse. Execute delays it for the flag sets of the ancestors of a
+// command until the arguments of the command have been parsed.
+func (f *FlagSet) finish() error {
+	if err := f.applyEnv(); err != nil {
+		return f.handleError(err)
+	}
+	if err := f.applySources(); err != nil {
+		return f.handleError(err)
+	}
+	if err := f.loadDotenvFlag(); err != nil {
+		return f.handleError(err)
+	}
+	if err := f.applyConfig(f.config); err != nil {
+		return f.handleError(err)
+	}
+	if f.printConfig != nil && isTrue(f.printConfig) {
+		f.PrintConfig()
+		return f.handleError(ErrPrintConfig)
+	}
+	if err := f.checkRequired(); err != nil {
+		return f.handleError(err)
+	}
+	if err := f.checkConstraints(); err != nil {
+		return f.handleError(err)
 	}
 	return nil
 }
 
+// handleError returns err, exits or panics as selected by the error
+// handling property of the flag set.
+func (f *FlagSet) handleError(err error) error {
+	switch f.errorHandling {
+	case ExitOnError:
+		if err == ErrPrintConfig || err == ErrVersion {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	case PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// checkRequired returns an error naming every required flag not set.
+func (f *FlagSet) checkRequired() error {
+	var missing []string
+	for _, flag := range sortFlags(f.formal) {
+		if _, ok := f.actual[flag.Name]; flag.Required && !ok {
+			missing = append(missing, "--"+flag.Name)
+		}
+	}
+	switch len(missing) {
+	case 0:
+		return nil
+	case 1:
+		return f.failf("required flag not set: %s", missing[0])
+	}
+	return f.failf("required flags not set: %s", strings.Join(missing, ", "))
+}
+
 // Parsed reports whether f.Parse has been called.
 func (f *FlagSet) Parsed() bool {
 	return f.parsed
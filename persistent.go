@@ -0,0 +1,150 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetParent makes the flags of parent, and those it inherits in turn,
+// available when parsing the arguments of f, as in "prog sub --verbose"
+// for a --verbose flag of prog. A flag of f is looked up first, so
+// definitions may not collide: SetParent panics if f or a flag set
+// inheriting from it defines a name or alias already defined by an
+// ancestor, and so does a later Var on any of these flag sets. Values
+// set through f are recorded in the flag set that defines the flag, and
+// PrintDefaults on f lists the inherited flags as global flags.
+func (f *FlagSet) SetParent(parent *FlagSet) {
+	for p := parent; p != nil; p = p.parent {
+		if p == f {
+			var msg string
+			if f.name == "" {
+				msg = "flag set is its own ancestor"
+			} else {
+				msg = fmt.Sprintf("%s flag set is its own ancestor", f.name)
+			}
+			fmt.Fprintln(f.Output(), msg)
+			panic(msg) // Happens only if flag sets are given a cyclic parent chain
+		}
+	}
+	if f.parent != nil {
+		siblings := f.parent.children
+		for i, child := range siblings {
+			if child == f {
+				f.parent.children = append(siblings[:i:i], siblings[i+1:]...)
+				break
+			}
+		}
+	}
+	f.parent = parent
+	if parent != nil {
+		parent.children = append(parent.children, f)
+	}
+	f.checkSubtree()
+}
+
+// checkSubtree panics if f or a flag set inheriting from it defines a
+// name or alias already defined by an ancestor, or a flag colliding with
+// the negation of an inherited flag.
+func (f *FlagSet) checkSubtree() {
+	for _, flag := range sortFlags(f.formal) {
+		f.checkInherited(flag.Name, flag.Alias)
+		f.checkNegation(flag)
+	}
+	for _, child := range f.children {
+		child.checkSubtree()
+	}
+}
+
+// Parent returns the flag set whose flags f inherits, or nil.
+func (f *FlagSet) Parent() *FlagSet {
+	return f.parent
+}
+
+// checkInherited panics if an ancestor of f defines name or alias.
+func (f *FlagSet) checkInherited(name string, alias rune) {
+	if f.parent == nil {
+		return
+	}
+	var msg string
+	if _, owner := f.parent.lookupFlag(name); owner != nil {
+		msg = fmt.Sprintf("%s flag redefined: %s is inherited from %s", f.name, name, owner.name)
+	} else if _, owner := f.parent.lookupAlias(alias); alias > 0 && owner != nil {
+		msg = fmt.Sprintf("%s flag redefined: %s as a %c is inherited from %s", f.name, name, alias, owner.name)
+	}
+	if msg != "" {
+		fmt.Fprintln(f.Output(), msg)
+		panic(msg) // Happens only if flags are declared with identical names
+	}
+}
+
+// checkDescendants panics if a flag set inheriting from f defines the
+// name or alias of flag, which f is about to define, or a flag colliding
+// with its negation.
+func (f *FlagSet) checkDescendants(flag *Flag) {
+	name, alias := flag.Name, flag.Alias
+	for _, child := range f.children {
+		var msg string
+		if _, ok := child.formal[name]; ok {
+			msg = fmt.Sprintf("%s flag redefined: %s is already defined by %s", f.name, name, child.name)
+		} else if _, ok := child.aliasToName[alias]; alias > 0 && ok {
+			msg = fmt.Sprintf("%s flag redefined: %s as a %c is already defined by %s", f.name, name, alias, child.name)
+		} else if _, ok := child.formal["no-"+name]; ok && f.Negatable && isNegatable(flag.Value) {
+			msg = fmt.Sprintf("%s flag %s collides with the negation of %s", child.name, "no-"+name, name)
+		} else if other, ok := child.formal[strings.TrimPrefix(name, "no-")]; ok && strings.HasPrefix(name, "no-") && child.Negatable && isNegatable(other.Value) {
+			msg = fmt.Sprintf("%s flag %s collides with the negation of %s", f.name, name, other.Name)
+		}
+		if msg != "" {
+			fmt.Fprintln(f.Output(), msg)
+			panic(msg) // Happens only if flags are declared with colliding names
+		}
+		child.checkDescendants(flag)
+	}
+}
+
+// lookupFlag returns the flag named name defined by f or its ancestors,
+// together with the flag set that defines it.
+func (f *FlagSet) lookupFlag(name string) (*Flag, *FlagSet) {
+	for p := f; p != nil; p = p.parent {
+		if flag, ok := p.formal[name]; ok {
+			return flag, p
+		}
+	}
+	return nil, nil
+}
+
+// lookupAlias returns the flag with the given alias defined by f or its
+// ancestors, together with the flag set that defines it.
+func (f *FlagSet) lookupAlias(alias rune) (*Flag, *FlagSet) {
+	for p := f; p != nil; p = p.parent {
+		if name, ok := p.aliasToName[alias]; ok {
+			return p.formal[name], p
+		}
+	}
+	return nil, nil
+}
+
+// inheritedFlag is a flag of an ancestor of a flag set.
+type inheritedFlag struct {
+	flag  *Flag
+	owner *FlagSet
+}
+
+// inherited returns the flags of the ancestors of f, nearest first and
+// each in lexicographical order.
+func (f *FlagSet) inherited() []inheritedFlag {
+	var flags []inheritedFlag
+	for p := f.parent; p != nil; p = p.parent {
+		for _, flag := range sortFlags(p.formal) {
+			flags = append(flags, inheritedFlag{flag: flag, owner: p})
+		}
+	}
+	return flags
+}
+
+// setActual records that flag, defined by f, has been set.
+func (f *FlagSet) setActual(flag *Flag) {
+	if f.actual == nil {
+		f.actual = make(map[string]*Flag)
+	}
+	f.actual[flag.Name] = flag
+}
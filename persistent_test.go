@@ -0,0 +1,188 @@
+package flags_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+func TestInheritedFlags(t *testing.T) {
+	root := NewFlagSet("prog", ContinueOnError, true)
+	root.Negatable = true
+	verbose := root.Bool("verbose", 'v', false, "verbose output", nil)
+	config := root.String("config", 'c', "", "config file", nil)
+	color := root.Bool("color", 0, true, "colored output", nil)
+
+	sub := NewFlagSet("sub", ContinueOnError, false)
+	name := sub.String("name", 'n', "", "name", nil)
+	sub.SetParent(root)
+
+	if err := root.Parse([]string{"-v", "sub", "-cfile", "--no-color", "arg", "--name=x"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sub.Parse(root.Args()[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if !*verbose || *config != "file" || *color || *name != "x" {
+		t.Errorf("got verbose=%t config=%q color=%t name=%q", *verbose, *config, *color, *name)
+	}
+	if root.NFlag() != 3 || sub.NFlag() != 1 {
+		t.Errorf("NFlag() = %d, %d; want 3, 1", root.NFlag(), sub.NFlag())
+	}
+	if len(sub.Args()) != 1 || sub.Args()[0] != "arg" {
+		t.Errorf("Args() = %q", sub.Args())
+	}
+}
+
+func TestInheritedFlagCollision(t *testing.T) {
+	data := []func(root, sub *FlagSet){
+		func(root, sub *FlagSet) { sub.SetParent(root); sub.Bool("verbose", 0, false, "", nil) },
+		func(root, sub *FlagSet) { sub.SetParent(root); sub.String("level", 'v', "", "", nil) },
+		func(root, sub *FlagSet) { sub.Int("verbose", 0, 0, "", nil); sub.SetParent(root) },
+		func(root, sub *FlagSet) { sub.SetParent(root); root.SetParent(sub) },
+		func(root, sub *FlagSet) {
+			root.Negatable = true
+			root.Bool("color", 0, true, "", nil)
+			sub.SetParent(root)
+			sub.Bool("no-color", 0, false, "", nil)
+		},
+		func(root, sub *FlagSet) {
+			root.Negatable = true
+			sub.Bool("no-color", 0, false, "", nil)
+			sub.SetParent(root)
+			root.Bool("color", 0, true, "", nil)
+		},
+		func(root, sub *FlagSet) {
+			root.Negatable = true
+			root.Bool("color", 0, true, "", nil)
+			sub.Bool("no-color", 0, false, "", nil)
+			sub.SetParent(root)
+		},
+		func(root, sub *FlagSet) {
+			sub.Negatable = true
+			sub.Bool("color", 0, true, "", nil)
+			sub.SetParent(root)
+			root.Bool("no-color", 0, false, "", nil)
+		},
+		func(root, sub *FlagSet) {
+			sub.SetParent(root)
+			sub.Int("level", 0, 0, "", nil)
+			root.Int("level", 0, 0, "", nil)
+		},
+		func(root, sub *FlagSet) {
+			sub.SetParent(root)
+			sub.Int("quiet", 'q', 0, "", nil)
+			root.Bool("silent", 'q', false, "", nil)
+		},
+		func(root, sub *FlagSet) {
+			leaf := NewFlagSet("leaf", ContinueOnError, false)
+			leaf.SetOutput(&bytes.Buffer{})
+			leaf.SetParent(sub)
+			leaf.Int("level", 0, 0, "", nil)
+			sub.SetParent(root)
+			root.Int("level", 0, 0, "", nil)
+		},
+		func(root, sub *FlagSet) {
+			leaf := NewFlagSet("leaf", ContinueOnError, false)
+			leaf.SetOutput(&bytes.Buffer{})
+			leaf.SetParent(sub)
+			leaf.Int("verbose", 0, 0, "", nil)
+			sub.SetParent(root)
+		},
+	}
+	for i, fn := range data {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("case %d did not panic", i)
+				}
+			}()
+			root := NewFlagSet("prog", ContinueOnError, false)
+			root.SetOutput(&bytes.Buffer{})
+			root.Bool("verbose", 'v', false, "", nil)
+			sub := NewFlagSet("sub", ContinueOnError, false)
+			sub.SetOutput(&bytes.Buffer{})
+			fn(root, sub)
+		}()
+	}
+}
+
+func TestInheritedFlagUsage(t *testing.T) {
+	root := NewFlagSet("prog", ContinueOnError, false)
+	root.Bool("verbose", 'v', false, "verbose output", nil)
+	sub := NewFlagSet("sub", ContinueOnError, false)
+	var buf bytes.Buffer
+	sub.SetOutput(&buf)
+	sub.String("name", 0, "x", "the `name`", nil)
+	sub.SetParent(root)
+	sub.PrintDefaults()
+	want := "  --name name\n    \tthe name (default \"x\")\n" +
+		"\nGlobal flags:\n" +
+		"  -v, --verbose\n    \tverbose output\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestCommandInheritedFlags(t *testing.T) {
+	var ran string
+	var rest []string
+	root, verbose, _ := newTestTree(&ran, &rest)
+	if err := root.Execute([]string{"remote", "add", "-fv", "origin"}); err != nil {
+		t.Fatal(err)
+	}
+	if !*verbose || ran != "prog remote add" {
+		t.Errorf("got verbose=%t ran=%q", *verbose, ran)
+	}
+}
+
+func TestCommandInheritedFlagChecks(t *testing.T) {
+	data := []struct {
+		args []string
+		ok   bool
+	}{
+		{args: []string{"sub", "--token", "x"}, ok: true},
+		{args: []string{"--token", "x", "sub"}, ok: true},
+		{args: []string{"sub"}},
+		{args: []string{"--token=x", "--json", "sub", "--yaml"}},
+		{args: []string{"sub", "--json", "--yaml", "--token=x"}},
+		{args: []string{"sub", "--yaml", "--token=x"}, ok: true},
+	}
+	for _, v := range data {
+		var ran bool
+		root := NewCommand("prog", "", nil)
+		root.Flags.SetOutput(&bytes.Buffer{})
+		token := root.Flags.String("token", 0, "", "", nil, Required())
+		root.Flags.Bool("json", 0, false, "", nil)
+		root.Flags.Bool("yaml", 0, false, "", nil)
+		root.Flags.MutuallyExclusive("json", "yaml")
+		sub := NewCommand("sub", "", func(*Command, []string) error {
+			ran = true
+			return nil
+		})
+		sub.Flags.SetOutput(&bytes.Buffer{})
+		root.AddCommand(sub)
+
+		err := root.Execute(v.args)
+		if v.ok && (err != nil || !ran || *token != "x") {
+			t.Errorf("%q: got %v, ran=%t, token=%q", v.args, err, ran, *token)
+		}
+		if !v.ok && (err == nil || ran) {
+			t.Errorf("%q: got %v, ran=%t; want an error", v.args, err, ran)
+		}
+	}
+}
+
+func TestInheritedFlagReparent(t *testing.T) {
+	a := NewFlagSet("a", ContinueOnError, false)
+	b := NewFlagSet("b", ContinueOnError, false)
+	sub := NewFlagSet("sub", ContinueOnError, false)
+	sub.Int("level", 0, 0, "", nil)
+	sub.SetParent(a)
+	sub.SetParent(b)
+	a.Int("level", 0, 0, "", nil) // sub no longer inherits from a
+	if sub.Parent() != b {
+		t.Error("Parent() is not the new parent")
+	}
+}
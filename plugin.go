@@ -0,0 +1,142 @@
+package flags
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// A PluginError reports that a plugin run by Execute failed. If the plugin
+// exited with a non-zero status, Code holds it. A command whose flag set
+// uses ExitOnError exits with the same status instead of returning the
+// error; otherwise a program should usually do so itself.
+type PluginError struct {
+	Name string // name of the subcommand, such as "foo"
+	Path string // path of the executable, such as "/usr/bin/prog-foo"
+	Code int    // exit status, or -1 if the plugin did not exit normally
+	Err  error
+}
+
+func (e *PluginError) Error() string {
+	return fmt.Sprintf("plugin %s: %v", e.Name, e.Err)
+}
+
+func (e *PluginError) Unwrap() error { return e.Err }
+
+// pluginPrefix returns the prefix of the executables that are plugins of
+// c, such as "prog-remote-" for "prog remote".
+func (c *Command) pluginPrefix() string {
+	return strings.Replace(c.CommandPath(), " ", "-", -1) + "-"
+}
+
+// pluginDirs returns the directories searched for plugins. Empty and
+// relative entries are left out, so that a plugin is never taken from
+// the current directory, and the paths of plugins are always absolute.
+func (c *Command) pluginDirs() []string {
+	path := c.PluginPath
+	if path == nil {
+		path = filepath.SplitList(os.Getenv("PATH"))
+	}
+	var dirs []string
+	for _, dir := range path {
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// isExecutable reports whether path names an executable regular file.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode()&0111 != 0
+}
+
+// lookPlugin returns the path of the plugin implementing the subcommand
+// name of c, or "" if there is none.
+func (c *Command) lookPlugin(name string) string {
+	if name == "" || strings.ContainsRune(name, filepath.Separator) || strings.HasPrefix(name, "-") {
+		return ""
+	}
+	for _, dir := range c.pluginDirs() {
+		if path := filepath.Join(dir, c.pluginPrefix()+name); isExecutable(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// Plugins returns the names of the plugins of c found in the plugin
+// directories, in sorted order, leaving out those shadowed by a built-in
+// subcommand.
+func (c *Command) Plugins() []string {
+	prefix := c.pluginPrefix()
+	seen := make(map[string]bool)
+	var names []string
+	for _, dir := range c.pluginDirs() {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := strings.TrimPrefix(entry.Name(), prefix)
+			if name == entry.Name() || name == "" || seen[name] || c.find(name) != nil {
+				continue
+			}
+			if isExecutable(filepath.Join(dir, entry.Name())) {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runPlugin runs the plugin at path for the subcommand name with args,
+// passing the values of PluginFlags in the environment.
+func (c *Command) runPlugin(name, path string, args []string) error {
+	env := os.Environ()
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+	prefix := envName("", root.Name) + "_"
+	for _, flagName := range c.PluginFlags {
+		flag, _ := c.Flags.lookupFlag(flagName)
+		if flag == nil {
+			return c.pluginFailed(&PluginError{Name: name, Path: path, Code: -1, Err: fmt.Errorf("no such flag --%s", flagName)})
+		}
+		env = append(env, envName(prefix, flag.Name)+"="+flag.Value.String())
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+	code := -1
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		code = exitErr.ExitCode()
+	}
+	return c.pluginFailed(&PluginError{Name: name, Path: path, Code: code, Err: err})
+}
+
+// pluginFailed handles the failure of a plugin as selected by the error
+// handling of the flag set of c. With ExitOnError, the program exits with
+// the status of the plugin, which printed its own messages, or with
+// status 2 after printing err if the plugin did not exit normally.
+func (c *Command) pluginFailed(err *PluginError) error {
+	if err.Code > 0 && c.Flags.errorHandling == ExitOnError {
+		os.Exit(err.Code)
+	}
+	return c.Flags.handleError(c.Flags.fail(err))
+}
@@ -0,0 +1,157 @@
+package flags_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+func pluginTree(t *testing.T) (*Command, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	dir := writeFiles(t, map[string]string{
+		"prog-foo":    "#!/bin/sh\necho \"$@\" \"$PROG_VERBOSE\" > \"$PLUGIN_TEST_OUT\"\nexit 3\n",
+		"prog-ok":     "#!/bin/sh\nexit 0\n",
+		"prog-status": "#!/bin/sh\nexit 1\n",
+		"prog-data":   "not executable",
+	})
+	for _, name := range []string{"prog-foo", "prog-ok", "prog-status"} {
+		if err := os.Chmod(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root := NewCommand("prog", "a test program", nil)
+	root.Flags.Bool("verbose", 'v', false, "verbose output", nil)
+	root.AddCommand(NewCommand("status", "show status", func(*Command, []string) error { return nil }))
+	root.EnablePlugins = true
+	root.PluginPath = []string{filepath.Join(dir, "missing"), dir}
+	root.PluginFlags = []string{"verbose"}
+	return root, dir
+}
+
+func TestPluginExecute(t *testing.T) {
+	root, dir := pluginTree(t)
+	out := filepath.Join(dir, "out")
+	setenv(t, "PLUGIN_TEST_OUT", out)
+
+	err := root.Execute([]string{"-v", "foo", "a", "--x"})
+	pe, ok := err.(*PluginError)
+	if !ok || pe.Name != "foo" || pe.Path != filepath.Join(dir, "prog-foo") || pe.Code != 3 {
+		t.Fatalf("Execute() = %#v; want *PluginError with code 3", err)
+	}
+	data, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "a --x true\n"; got != want {
+		t.Errorf("plugin got %q; want %q", got, want)
+	}
+
+	if err := root.Execute([]string{"ok"}); err != nil {
+		t.Errorf("Execute(ok) = %v", err)
+	}
+	if err := root.Execute([]string{"status"}); err != nil {
+		t.Errorf("Execute(status) = %v; the built-in command should win", err)
+	}
+}
+
+func TestPluginDiscovery(t *testing.T) {
+	root, _ := pluginTree(t)
+	if got, want := root.Plugins(), []string{"foo", "ok"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Plugins() = %q; want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	root.Flags.SetOutput(&buf)
+	err := root.Execute([]string{"bar"})
+	if want := `unknown command "bar" for "prog"; valid commands: status, foo, ok`; err == nil || err.Error() != want {
+		t.Errorf("got %v; want %s", err, want)
+	}
+
+	buf.Reset()
+	root.Usage()
+	if want := "\nPlugins:\n  foo\n  ok\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("usage %q does not list the plugins", buf.String())
+	}
+}
+
+func TestPluginCommandError(t *testing.T) {
+	root, dir := pluginTree(t)
+	root.Flags.SetOutput(&bytes.Buffer{})
+	err := root.Execute([]string{"bar"})
+	ce, ok := err.(*CommandError)
+	if want := []string{"status", "foo", "ok"}; !ok || !reflect.DeepEqual(ce.Valid, want) {
+		t.Fatalf("got %#v; want a *CommandError listing %q", err, want)
+	}
+
+	msg := ce.Error()
+	if err := os.Remove(filepath.Join(dir, "prog-ok")); err != nil {
+		t.Fatal(err)
+	}
+	if ce.Error() != msg {
+		t.Errorf("message changed to %q after removing a plugin", ce.Error())
+	}
+}
+
+func TestPluginRelativePath(t *testing.T) {
+	root, dir := pluginTree(t)
+	root.Flags.SetOutput(&bytes.Buffer{})
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	root.PluginPath = []string{"", ".", "sub/.."}
+	if got := root.Plugins(); len(got) != 0 {
+		t.Errorf("Plugins() = %q; want none from relative directories", got)
+	}
+	if _, ok := root.Execute([]string{"ok"}).(*CommandError); !ok {
+		t.Error("a plugin in the current directory was run")
+	}
+}
+
+func TestPluginHelp(t *testing.T) {
+	root, dir := pluginTree(t)
+	setenv(t, "PLUGIN_TEST_OUT", filepath.Join(dir, "out"))
+	if err := root.Execute([]string{"help", "ok"}); err != ErrHelp {
+		t.Errorf("help ok = %v; want ErrHelp", err)
+	}
+	if _, ok := root.Execute([]string{"help", "foo"}).(*PluginError); !ok {
+		t.Error("help foo should report the failure of the plugin")
+	}
+}
+
+func TestPluginExitStatus(t *testing.T) {
+	if os.Getenv("PLUGIN_TEST_EXIT") == "1" {
+		root, dir := pluginTree(t)
+		root.Flags = NewFlagSet("prog", ExitOnError, false)
+		setenv(t, "PLUGIN_TEST_OUT", filepath.Join(dir, "out"))
+		root.PluginFlags = nil
+		root.Execute([]string{"foo"})
+		os.Exit(0)
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestPluginExitStatus$")
+	cmd.Env = append(os.Environ(), "PLUGIN_TEST_EXIT=1")
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 3 {
+		t.Errorf("got %v; want exit status 3 from the plugin", err)
+	}
+}
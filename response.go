@@ -0,0 +1,165 @@
+package flags
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// expandResponseFile replaces the response file argument at f.index, given
+// as @path or as --name=path or --name path with name the FlagFile of the
+// flag set, with the arguments read from the file. It reports whether
+// there was such an argument.
+func (f *FlagSet) expandResponseFile() (bool, error) {
+	s := f.args[f.index]
+	n, path := 1, ""
+	switch {
+	case f.ResponseFiles && len(s) > 1 && s[0] == '@':
+		path = s[1:]
+	case f.FlagFile != "" && s == "--"+f.FlagFile:
+		if f.index+1 >= len(f.args) {
+			return true, f.failf("flag needs an argument: --%s", f.FlagFile)
+		}
+		n, path = 2, f.args[f.index+1]
+	case f.FlagFile != "" && strings.HasPrefix(s, "--"+f.FlagFile+"="):
+		path = s[len(f.FlagFile)+3:]
+	default:
+		return false, nil
+	}
+
+	args, err := f.readResponseFile(path, nil)
+	if err != nil {
+		return true, f.fail(err)
+	}
+	rest := append(args, f.args[f.index+n:]...)
+	f.args = append(f.args[:f.index:f.index], rest...)
+	return true, nil
+}
+
+// readResponseFile returns the arguments read from the named file, with
+// the response files it names expanded in turn. Relative paths in a file
+// are taken relative to its directory. The stack holds the files being
+// read, to detect cycles.
+func (f *FlagSet) readResponseFile(path string, stack []string) ([]string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("response file %s: %v", path, err)
+	}
+	for i, p := range stack {
+		if p == abs {
+			return nil, fmt.Errorf("response file cycle: %s -> %s", strings.Join(stack[i:], " -> "), abs)
+		}
+	}
+	stack = append(stack, abs)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("response file %s: %v", path, err)
+	}
+	tokens, err := splitResponseFile(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("response file %s: %v", path, err)
+	}
+
+	var args []string
+	include := func(name string) error {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(filepath.Dir(path), name)
+		}
+		nested, err := f.readResponseFile(name, stack)
+		args = append(args, nested...)
+		return err
+	}
+	for i := 0; i < len(tokens); i++ {
+		s := tokens[i]
+		switch {
+		case f.ResponseFiles && len(s) > 1 && s[0] == '@':
+			err = include(s[1:])
+		case f.FlagFile != "" && s == "--"+f.FlagFile:
+			if i+1 >= len(tokens) {
+				return nil, fmt.Errorf("response file %s: flag needs an argument: --%s", path, f.FlagFile)
+			}
+			i++
+			err = include(tokens[i])
+		case f.FlagFile != "" && strings.HasPrefix(s, "--"+f.FlagFile+"="):
+			err = include(s[len(f.FlagFile)+3:])
+		default:
+			args = append(args, s)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// splitResponseFile splits the contents of a response file into
+// arguments, as a POSIX shell would split a command line without
+// expansions. Arguments are separated by white space. Single quotes
+// preserve every character, double quotes preserve all but a backslash
+// escaping '"', '\\', '$' or '`', and a backslash outside quotes escapes
+// any character. A '#' starting an argument starts a comment that runs to
+// the end of the line.
+func splitResponseFile(s string) ([]string, error) {
+	var args []string
+	var b strings.Builder
+	inArg := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, b.String())
+				b.Reset()
+				inArg = false
+			}
+		case c == '#' && !inArg:
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '\\':
+			if i+1 < len(s) {
+				i++
+				if s[i] == '\n' {
+					// A backslash-newline is a line continuation.
+					continue
+				}
+				b.WriteByte(s[i])
+			}
+			inArg = true
+		case c == '\'':
+			inArg = true
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			b.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			inArg = true
+			for i++; ; i++ {
+				if i >= len(s) {
+					return nil, fmt.Errorf("unterminated double quote")
+				}
+				if s[i] == '"' {
+					break
+				}
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				b.WriteByte(s[i])
+			}
+		default:
+			inArg = true
+			b.WriteByte(c)
+		}
+	}
+	if inArg {
+		args = append(args, b.String())
+	}
+	return args, nil
+}
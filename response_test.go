@@ -0,0 +1,106 @@
+package flags_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "flags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestResponseFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"args.txt": `# build arguments
+--name 'hello world' --define="a=\"b\"" \
+  @sub/more.txt
+positional\ arg
+`,
+		"sub/more.txt": "--define c=d --level 3 --flagfile=last.txt\n",
+		"sub/last.txt": "--verbose\n",
+	})
+
+	fs := NewFlagSet("response test", ContinueOnError, false)
+	fs.ResponseFiles = true
+	fs.FlagFile = "flagfile"
+	name := fs.String("name", 0, "", "", nil)
+	level := fs.Int("level", 0, 0, "", nil)
+	verbose := fs.Bool("verbose", 0, false, "", nil)
+	defines := fs.StringSlice("define", 0, nil, "", nil)
+
+	args := []string{"first", "@" + filepath.Join(dir, "args.txt"), "--level", "5", "--", "@literal"}
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	if *name != "hello world" || *level != 5 || !*verbose {
+		t.Errorf("got name=%q level=%d verbose=%t", *name, *level, *verbose)
+	}
+	if want := []string{`a="b"`, "c=d"}; !reflect.DeepEqual(*defines, want) {
+		t.Errorf("defines = %q; want %q", *defines, want)
+	}
+	if want := []string{"first", "positional arg", "@literal"}; !reflect.DeepEqual(fs.Args(), want) {
+		t.Errorf("Args() = %q; want %q", fs.Args(), want)
+	}
+}
+
+func TestFlagFileSeparateArgument(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"flags": "--level=2"})
+
+	fs := NewFlagSet("response test", ContinueOnError, false)
+	fs.FlagFile = "flagfile"
+	level := fs.Int("level", 0, 0, "", nil)
+	if err := fs.Parse([]string{"--flagfile", filepath.Join(dir, "flags"), "@kept"}); err != nil {
+		t.Fatal(err)
+	}
+	if *level != 2 || !reflect.DeepEqual(fs.Args(), []string{"@kept"}) {
+		t.Errorf("got level=%d args=%q", *level, fs.Args())
+	}
+}
+
+func TestResponseFileErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.txt":     "@b.txt",
+		"b.txt":     "@a.txt",
+		"quote.txt": "--name 'open",
+	})
+
+	data := []struct {
+		arg  string
+		want string
+	}{
+		{arg: "@" + filepath.Join(dir, "a.txt"), want: "response file cycle"},
+		{arg: "@" + filepath.Join(dir, "quote.txt"), want: "unterminated single quote"},
+		{arg: "@" + filepath.Join(dir, "missing.txt"), want: "missing.txt"},
+	}
+
+	for _, v := range data {
+		fs := NewFlagSet("response test", ContinueOnError, false)
+		fs.SetOutput(&bytes.Buffer{})
+		fs.ResponseFiles = true
+		fs.String("name", 0, "", "", nil)
+		if err := fs.Parse([]string{v.arg}); err == nil || !strings.Contains(err.Error(), v.want) {
+			t.Errorf("Parse(%q) = %v; want error containing %q", v.arg, err, v.want)
+		}
+	}
+}
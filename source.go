@@ -0,0 +1,163 @@
+package flags
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A Source supplies flag values from a configuration backend, such as a
+// key-value store or a directory of secrets. Parse consults the sources
+// added with AddSource for the flags not set otherwise.
+type Source interface {
+	// Name identifies the source in errors and in Flag.Location.
+	Name() string
+
+	// Lookup returns the value stored under key, and whether there is one.
+	Lookup(key string) (value string, ok bool, err error)
+}
+
+// source is a Source added to a flag set.
+type source struct {
+	src    Source
+	prefix string
+}
+
+// AddSource adds src to the sources that Parse consults, in the order
+// added, for each flag that takes its value from a source in the order of
+// precedence described at Parse. The key looked up is the flag name after
+// prefix, and the first source holding the key sets the flag. Errors are
+// of type *ConfigError and name the source and key.
+func (f *FlagSet) AddSource(src Source, prefix string) {
+	f.sources = append(f.sources, source{src: src, prefix: prefix})
+}
+
+// AddSource adds src to the sources consulted by Parse for the
+// command-line flags. See FlagSet.AddSource.
+func AddSource(src Source, prefix string) {
+	CommandLine.AddSource(src, prefix)
+}
+
+// applySources sets each flag not yet set from the first source holding it.
+func (f *FlagSet) applySources() error {
+	if len(f.sources) == 0 {
+		return nil
+	}
+	for _, flag := range sortFlags(f.formal) {
+		if _, ok := f.actual[flag.Name]; ok {
+			continue
+		}
+		for _, s := range f.sources {
+			key := s.prefix + flag.Name
+			value, src, ok, err := lookupSource(s.src, key)
+			if err != nil {
+				return f.fail(&ConfigError{Source: src.Name(), Path: key, Flag: flag.Name, Err: err})
+			}
+			if !ok {
+				continue
+			}
+			if err := flag.Value.Set(value); err != nil {
+				err = fmt.Errorf("invalid value %q: %v", value, err)
+				return f.fail(&ConfigError{Source: src.Name(), Path: key, Flag: flag.Name, Err: err})
+			}
+			flag.record(OriginSource, value, src.Name()+": "+key)
+			if err := f.callback(flag); err != nil {
+				return err
+			}
+			if f.actual == nil {
+				f.actual = make(map[string]*Flag)
+			}
+			f.actual[flag.Name] = flag
+			break
+		}
+	}
+	return nil
+}
+
+// lookupSource looks key up in src and returns the source that answered,
+// which is one of the sources of a chain.
+func lookupSource(src Source, key string) (string, Source, bool, error) {
+	if c, ok := src.(chainSource); ok {
+		for _, s := range c.sources {
+			if value, found, ok, err := lookupSource(s, key); ok || err != nil {
+				return value, found, ok, err
+			}
+		}
+		return "", src, false, nil
+	}
+	value, ok, err := src.Lookup(key)
+	return value, src, ok, err
+}
+
+// -- map Source
+type mapSource struct {
+	name   string
+	values map[string]string
+}
+
+// MapSource returns a Source with the given name holding the values of m.
+// It is useful for tests and for values computed by the program.
+func MapSource(name string, m map[string]string) Source {
+	return &mapSource{name: name, values: m}
+}
+
+func (s *mapSource) Name() string { return s.name }
+
+func (s *mapSource) Lookup(key string) (string, bool, error) {
+	value, ok := s.values[key]
+	return value, ok, nil
+}
+
+// -- directory Source
+type dirSource string
+
+// DirSource returns a Source holding one value per file in dir, such as a
+// Docker or Kubernetes secrets mount. The key is the file name and the
+// value is the contents of the file without a trailing newline.
+func DirSource(dir string) Source {
+	return dirSource(dir)
+}
+
+func (s dirSource) Name() string { return string(s) }
+
+func (s dirSource) Lookup(key string) (string, bool, error) {
+	if key == "" || filepath.Base(key) != key || key == "." || key == ".." {
+		return "", false, nil
+	}
+	data, err := ioutil.ReadFile(filepath.Join(string(s), key))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	value := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(value, "\r"), true, nil
+}
+
+// -- chain Source
+type chainSource struct {
+	sources []Source
+}
+
+// ChainSource returns a Source that looks a key up in each of sources in
+// turn and returns the first value found. Errors and Flag.Location name
+// the source in the chain that held the value.
+func ChainSource(sources ...Source) Source {
+	return chainSource{sources: sources}
+}
+
+func (c chainSource) Name() string {
+	names := make([]string, len(c.sources))
+	for i, s := range c.sources {
+		names[i] = s.Name()
+	}
+	return strings.Join(names, ", ")
+}
+
+func (c chainSource) Lookup(key string) (string, bool, error) {
+	value, _, ok, err := lookupSource(c, key)
+	return value, ok, err
+}
@@ -0,0 +1,54 @@
+package flags_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+func TestSources(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app_db-password": "s3cret\n",
+		"app_port":        "9000\n",
+	})
+
+	fs := NewFlagSet("source test", ContinueOnError, false)
+	port := fs.Int("port", 0, 80, "", nil)
+	password := fs.String("db-password", 0, "", "", nil)
+	host := fs.String("host", 0, "localhost", "", nil)
+	user := fs.String("user", 0, "nobody", "", nil)
+	level := fs.Int("level", 0, 0, "", nil)
+
+	fs.AddSource(MapSource("cache", map[string]string{"host": "cache.example.com", "port": "7000"}), "")
+	fs.AddSource(ChainSource(DirSource(dir), MapSource("fake", map[string]string{"app_user": "gopher"})), "app_")
+	if err := fs.LoadJSON(strings.NewReader(`{"user": "file", "level": 3}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"--host", "cli"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if *port != 7000 || *password != "s3cret" || *host != "cli" || *user != "gopher" || *level != 3 {
+		t.Errorf("got port=%d password=%q host=%q user=%q level=%d", *port, *password, *host, *user, *level)
+	}
+	if flag := fs.Lookup("db-password"); flag.Origin != OriginSource || flag.Location != dir+": app_db-password" {
+		t.Errorf("db-password: got %v %q", flag.Origin, flag.Location)
+	}
+	if flag := fs.Lookup("user"); flag.Origin != OriginSource || flag.Location != "fake: app_user" {
+		t.Errorf("user: got %v %q", flag.Origin, flag.Location)
+	}
+}
+
+func TestSourceError(t *testing.T) {
+	fs := NewFlagSet("source test", ContinueOnError, false)
+	fs.SetOutput(&bytes.Buffer{})
+	fs.Int("port", 0, 80, "", nil)
+	fs.AddSource(ChainSource(MapSource("empty", nil), MapSource("bad", map[string]string{"port": "x"})), "")
+
+	err := fs.Parse(nil)
+	if ce, ok := err.(*ConfigError); !ok || ce.Source != "bad" || ce.Path != "port" || ce.Flag != "port" {
+		t.Errorf("got %#v; want error from source bad", err)
+	}
+}
@@ -94,7 +94,7 @@ func UnquoteUsage(flag *Flag) (name string, usage string) {
 	}
 	// No explicit name, so use type if we can find one.
 	name = "value"
-	switch flag.Value.(type) {
+	switch v := flag.Value.(type) {
 	case boolFlag:
 		name = ""
 	case *durationValue:
@@ -105,49 +105,103 @@ func UnquoteUsage(flag *Flag) (name string, usage string) {
 		name = "int"
 	case *stringValue:
 		name = "string"
+	case *timeValue:
+		name = "time"
 	case *uintValue, *uint64Value:
 		name = "uint"
+	case *stringSliceValue:
+		name = "strings"
+	case *intSliceValue:
+		name = "ints"
+	case *float64SliceValue:
+		name = "floats"
+	case *durationSliceValue:
+		name = "durations"
+	case interface{ typeName() string }:
+		name = v.typeName()
+	case *enumValue:
+		name = "{" + strings.Join(v.choices, "|") + "}"
 	}
 	return
 }
 
+// names returns the alias and long name of flag as shown in usage messages.
+func (f *FlagSet) names(flag *Flag) string {
+	name := flag.Name
+	if f.Negatable && isNegatable(flag.Value) {
+		name = "[no-]" + name
+	}
+	if flag.Alias > 0 {
+		return fmt.Sprintf("  -%c, --%s", flag.Alias, name)
+	}
+	return fmt.Sprintf("  --%s", name)
+}
+
 // PrintDefaults prints, to standard error unless configured otherwise, the
 // default values of all defined command-line flags in the set. See the
 // documentation for the global function PrintDefaults for more information.
 func (f *FlagSet) PrintDefaults() {
 	f.VisitAll(func(flag *Flag) {
-		s := ""
-		if flag.Alias > 0 {
-			s = fmt.Sprintf("  -%c, --%s", flag.Alias, flag.Name)
-		} else {
-			s = fmt.Sprintf("  --%s", flag.Name)
+		if !flag.Hidden || f.showHidden {
+			fmt.Fprint(f.Output(), f.flagUsage(flag), "\n")
+		}
+	})
+	var global []string
+	for _, v := range f.inherited() {
+		if !v.flag.Hidden || f.showHidden {
+			global = append(global, v.owner.flagUsage(v.flag))
 		}
+	}
+	if len(global) > 0 {
+		fmt.Fprintf(f.Output(), "\nGlobal flags:\n%s\n", strings.Join(global, "\n"))
+	}
+}
+
+// flagUsage returns the lines printed by PrintDefaults for flag.
+func (f *FlagSet) flagUsage(flag *Flag) string {
+	s := f.names(flag)
 
-		name, usage := UnquoteUsage(flag)
-		if len(name) > 0 {
+	name, usage := UnquoteUsage(flag)
+	if len(name) > 0 {
+		if flag.NoOptDefVal != "" {
+			s += "[=" + name + "]"
+		} else {
 			s += " " + name
 		}
-		// Boolean flags of one ASCII letter are so common we
-		// treat them specially, putting their usage on the same line.
-		if len(s) <= 4 { // space, space, '-', 'x'.
-			s += "\t"
+	}
+	// Boolean flags of one ASCII letter are so common we
+	// treat them specially, putting their usage on the same line.
+	if len(s) <= 4 { // space, space, '-', 'x'.
+		s += "\t"
+	} else {
+		// Four spaces before the tab triggers good alignment
+		// for both 4- and 8-space tab stops.
+		s += "\n    \t"
+	}
+	s += strings.ReplaceAll(usage, "\n", "\n    \t")
+
+	if keys := f.envVars(flag); len(keys) > 0 {
+		s += " [$" + strings.Join(keys, ", $") + "]"
+	}
+	if !isZeroValue(flag, flag.DefValue) {
+		if _, ok := flag.Value.(*stringValue); ok {
+			// put quotes on the value
+			s += fmt.Sprintf(" (default %q)", flag.DefValue)
 		} else {
-			// Four spaces before the tab triggers good alignment
-			// for both 4- and 8-space tab stops.
-			s += "\n    \t"
+			s += fmt.Sprintf(" (default %v)", flag.DefValue)
 		}
-		s += strings.ReplaceAll(usage, "\n", "\n    \t")
-
-		if !isZeroValue(flag, flag.DefValue) {
-			if _, ok := flag.Value.(*stringValue); ok {
-				// put quotes on the value
-				s += fmt.Sprintf(" (default %q)", flag.DefValue)
-			} else {
-				s += fmt.Sprintf(" (default %v)", flag.DefValue)
+	}
+	if flag.Required {
+		s += " (required)"
+	}
+	if e, ok := flag.Value.(*enumValue); ok {
+		for _, c := range e.choices {
+			if u := e.ChoiceUsage(c); u != "" {
+				s += fmt.Sprintf("\n    \t  %s: %s", c, u)
 			}
 		}
-		fmt.Fprint(f.Output(), s, "\n")
-	})
+	}
+	return s
 }
 
 // PrintDefaults prints, to standard error unless configured otherwise,
@@ -183,6 +237,7 @@ func (f *FlagSet) defaultUsage() {
 		fmt.Fprintf(f.Output(), "\nUsage: %s\n", f.name)
 	}
 	f.PrintDefaults()
+	f.printConstraints()
 }
 
 // NOTE: Usage is not just defaultUsage(CommandLine)
@@ -205,12 +260,10 @@ var Usage = func() {
 
 func (f *FlagSet) PrintCustom() {
 	f.VisitAll(func(flag *Flag) {
-		s := ""
-		if flag.Alias > 0 {
-			s = fmt.Sprintf("  -%c, --%s", flag.Alias, flag.Name)
-		} else {
-			s = fmt.Sprintf("  --%s", flag.Name)
+		if flag.Hidden && !f.showHidden {
+			return
 		}
+		s := f.names(flag)
 
 		_, usage := UnquoteUsage(flag)
 		if len(s) <= 4 {
@@ -9,11 +9,6 @@ import "strconv"
 // -- bool Value
 type boolValue bool
 
-func newBoolValue(val bool, p *bool) *boolValue {
-	*p = val
-	return (*boolValue)(p)
-}
-
 func (b *boolValue) Set(s string) error {
 	v, err := strconv.ParseBool(s)
 	if err != nil {
@@ -36,28 +31,49 @@ type boolFlag interface {
 	IsBoolFlag() bool
 }
 
+// isBoolFlag reports whether v can be supplied without "=value" text.
+func isBoolFlag(v Value) bool {
+	b, ok := v.(boolFlag)
+	return ok && b.IsBoolFlag()
+}
+
+// isNegatable reports whether v holds a boolean that --no-name may set to
+// false. Values such as counters are supplied without "=value" text too,
+// but are not booleans.
+func isNegatable(v Value) bool {
+	if !isBoolFlag(v) {
+		return false
+	}
+	g, ok := v.(Getter)
+	if !ok {
+		return true
+	}
+	_, ok = g.Get().(bool)
+	return ok
+}
+
 // BoolVar defines a bool flag with specified name, default value, and usage string.
 // The argument p points to a bool variable in which to store the value of the flag.
-func (f *FlagSet) BoolVar(p *bool, name string, alias rune, value bool, usage string, fn Callback) {
-	f.Var(newBoolValue(value, p), name, alias, usage, fn)
+func (f *FlagSet) BoolVar(p *bool, name string, alias rune, value bool, usage string, fn Callback, opts ...Option) {
+	define(f, p, name, alias, value, usage, fn, opts)
 }
 
 // BoolVar defines a bool flag with specified name, default value, and usage string.
 // The argument p points to a bool variable in which to store the value of the flag.
-func BoolVar(p *bool, name string, alias rune, value bool, usage string, fn Callback) {
-	CommandLine.Var(newBoolValue(value, p), name, alias, usage, fn)
+func BoolVar(p *bool, name string, alias rune, value bool, usage string, fn Callback, opts ...Option) {
+	define(CommandLine, p, name, alias, value, usage, fn, opts)
 }
 
 // Bool defines a bool flag with specified name, default value, and usage string.
 // The return value is the address of a bool variable that stores the value of the flag.
-func (f *FlagSet) Bool(name string, alias rune, value bool, usage string, fn Callback) *bool {
+func (f *FlagSet) Bool(name string, alias rune, value bool, usage string, fn Callback, opts ...Option) *bool {
 	p := new(bool)
-	f.BoolVar(p, name, alias, value, usage, fn)
+	f.BoolVar(p, name, alias, value, usage, fn, opts...)
 	return p
 }
 
 // Bool defines a bool flag with specified name, default value, and usage string.
 // The return value is the address of a bool variable that stores the value of the flag.
-func Bool(name string, alias rune, value bool, usage string, fn Callback) *bool {
-	return CommandLine.Bool(name, alias, value, usage, fn)
+func Bool(name string, alias rune, value bool, usage string, fn Callback, opts ...Option) *bool {
+	return CommandLine.Bool(name, alias, value, usage, fn, opts...)
 }
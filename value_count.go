@@ -0,0 +1,71 @@
+package flags
+
+import "strconv"
+
+// -- count Value
+type countValue int
+
+func newCountValue(val int, p *int) *countValue {
+	*p = val
+	return (*countValue)(p)
+}
+
+// Set increments the count for "true", which the parser passes for every
+// occurrence of the flag without a value, and resets it for "false".
+// Any other value must be an integer and replaces the count.
+func (c *countValue) Set(s string) error {
+	switch s {
+	case "true":
+		*c++
+		return nil
+	case "false":
+		*c = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(s, 0, strconv.IntSize)
+	if err != nil {
+		return numError(err)
+	}
+	*c = countValue(v)
+	return nil
+}
+
+func (c *countValue) Get() interface{} { return int(*c) }
+
+func (c *countValue) String() string { return strconv.Itoa(int(*c)) }
+
+func (c *countValue) IsBoolFlag() bool { return true }
+
+// CountVar defines a count flag with specified name, default value, and usage string.
+// The argument p points to an int variable in which to store the value of the flag.
+// Every occurrence of the flag without a value increments it, so that -vvv and
+// --verbose --verbose --verbose both add 3; --verbose=5 sets it explicitly.
+func (f *FlagSet) CountVar(p *int, name string, alias rune, value int, usage string, fn Callback, opts ...Option) {
+	f.Var(newCountValue(value, p), name, alias, usage, fn, opts...)
+}
+
+// CountVar defines a count flag with specified name, default value, and usage string.
+// The argument p points to an int variable in which to store the value of the flag.
+// Every occurrence of the flag without a value increments it, so that -vvv and
+// --verbose --verbose --verbose both add 3; --verbose=5 sets it explicitly.
+func CountVar(p *int, name string, alias rune, value int, usage string, fn Callback, opts ...Option) {
+	CommandLine.Var(newCountValue(value, p), name, alias, usage, fn, opts...)
+}
+
+// Count defines a count flag with specified name, default value, and usage string.
+// The return value is the address of an int variable that stores the value of the flag.
+// Every occurrence of the flag without a value increments it, so that -vvv and
+// --verbose --verbose --verbose both add 3; --verbose=5 sets it explicitly.
+func (f *FlagSet) Count(name string, alias rune, value int, usage string, fn Callback, opts ...Option) *int {
+	p := new(int)
+	f.CountVar(p, name, alias, value, usage, fn, opts...)
+	return p
+}
+
+// Count defines a count flag with specified name, default value, and usage string.
+// The return value is the address of an int variable that stores the value of the flag.
+// Every occurrence of the flag without a value increments it, so that -vvv and
+// --verbose --verbose --verbose both add 3; --verbose=5 sets it explicitly.
+func Count(name string, alias rune, value int, usage string, fn Callback, opts ...Option) *int {
+	return CommandLine.Count(name, alias, value, usage, fn, opts...)
+}
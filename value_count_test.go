@@ -0,0 +1,38 @@
+package flags_test
+
+import (
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+func TestCountFlag(t *testing.T) {
+	data := []struct {
+		a []string
+		e int
+		r []string // remainder argument
+	}{
+		{a: []string{"-vvv"}, e: 3},
+		{a: []string{"--verbose", "--verbose"}, e: 2},
+		{a: []string{"-v", "arg", "-v"}, e: 2, r: []string{"arg"}},
+		{a: []string{"--verbose=5", "-v"}, e: 6},
+		{a: []string{"-vb", "--verbose=false"}, e: 0},
+	}
+
+	for _, v := range data {
+		flags := NewFlagSet("", ContinueOnError, false)
+		verbose := flags.Count("verbose", 'v', 0, "", nil)
+		flags.Bool("bool", 'b', false, "", nil)
+
+		if err := flags.Parse(v.a); err != nil {
+			t.Errorf("Parse(%q): %v", v.a, err)
+			continue
+		}
+		if *verbose != v.e {
+			t.Errorf("Parse(%q): count = %d; want %d", v.a, *verbose, v.e)
+		}
+		if len(flags.Args()) != len(v.r) {
+			t.Errorf("Parse(%q): args = %q; want %q", v.a, flags.Args(), v.r)
+		}
+	}
+}
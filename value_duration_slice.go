@@ -0,0 +1,76 @@
+package flags
+
+import "time"
+
+// -- []time.Duration Value
+type durationSliceValue struct {
+	sliceValue
+	value *[]time.Duration
+}
+
+func newDurationSliceValue(val []time.Duration, p *[]time.Duration) *durationSliceValue {
+	*p = val
+	return &durationSliceValue{sliceValue: sliceValue{sep: ','}, value: p}
+}
+
+func (s *durationSliceValue) Set(val string) error {
+	elems, err := s.split(val)
+	if err != nil {
+		return err
+	}
+	vals := make([]time.Duration, len(elems))
+	for i, e := range elems {
+		if err := newDurationValue(0, &vals[i]).Set(e); err != nil {
+			return err
+		}
+	}
+	if s.replace() {
+		*s.value = vals
+	} else {
+		*s.value = append(*s.value, vals...)
+	}
+	return nil
+}
+
+func (s *durationSliceValue) Get() interface{} { return append([]time.Duration(nil), *s.value...) }
+
+func (s *durationSliceValue) String() string {
+	if s.value == nil {
+		return "[]"
+	}
+	elems := make([]string, len(*s.value))
+	for i, v := range *s.value {
+		elems[i] = v.String()
+	}
+	return s.join(elems)
+}
+
+// DurationSliceVar defines a []time.Duration flag with specified name, default value, and usage string.
+// The argument p points to a []time.Duration variable in which to store the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func (f *FlagSet) DurationSliceVar(p *[]time.Duration, name string, alias rune, value []time.Duration, usage string, fn Callback, opts ...Option) {
+	f.Var(newDurationSliceValue(value, p), name, alias, usage, fn, opts...)
+}
+
+// DurationSliceVar defines a []time.Duration flag with specified name, default value, and usage string.
+// The argument p points to a []time.Duration variable in which to store the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func DurationSliceVar(p *[]time.Duration, name string, alias rune, value []time.Duration, usage string, fn Callback, opts ...Option) {
+	CommandLine.Var(newDurationSliceValue(value, p), name, alias, usage, fn, opts...)
+}
+
+// DurationSlice defines a []time.Duration flag with specified name, default value, and usage string.
+// The return value is the address of a []time.Duration variable that stores the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func (f *FlagSet) DurationSlice(name string, alias rune, value []time.Duration, usage string, fn Callback, opts ...Option) *[]time.Duration {
+	p := new([]time.Duration)
+	f.DurationSliceVar(p, name, alias, value, usage, fn, opts...)
+	return p
+}
+
+// DurationSlice defines a []time.Duration flag with specified name, default value, and usage string.
+// The return value is the address of a []time.Duration variable that stores the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func DurationSlice(name string, alias rune, value []time.Duration, usage string, fn Callback, opts ...Option) *[]time.Duration {
+	return CommandLine.DurationSlice(name, alias, value, usage, fn, opts...)
+}
@@ -0,0 +1,113 @@
+package flags
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnumValue is the interface implemented by the flags defined by Enum
+// and EnumVar, which accept only one of a fixed set of choices.
+type EnumValue interface {
+	Getter
+	// Choices returns the allowed values in the order they were defined.
+	Choices() []string
+	// SetCaseInsensitive makes Set accept the choices in any case. The
+	// value is stored with the case used in the definition.
+	SetCaseInsensitive(fold bool)
+	// DescribeChoice sets the help message shown for a single choice.
+	DescribeChoice(choice, usage string)
+	// ChoiceUsage returns the help message of a single choice.
+	ChoiceUsage(choice string) string
+}
+
+// -- enum Value
+type enumValue struct {
+	value   *string
+	choices []string
+	usage   map[string]string
+	fold    bool
+}
+
+func newEnumValue(val string, choices []string, p *string) *enumValue {
+	e := &enumValue{value: p, choices: append([]string(nil), choices...)}
+	if val != "" && e.match(val) == "" {
+		panic(fmt.Sprintf("flag: default %q is not one of %s", val, strings.Join(choices, ", ")))
+	}
+	*p = val
+	return e
+}
+
+// match returns the choice equal to s, or "" if there is none.
+func (e *enumValue) match(s string) string {
+	for _, c := range e.choices {
+		if c == s || (e.fold && strings.EqualFold(c, s)) {
+			return c
+		}
+	}
+	return ""
+}
+
+func (e *enumValue) Set(s string) error {
+	c := e.match(s)
+	if c == "" {
+		return fmt.Errorf("must be one of %s", strings.Join(e.choices, ", "))
+	}
+	*e.value = c
+	return nil
+}
+
+func (e *enumValue) Get() interface{} { return *e.value }
+
+func (e *enumValue) String() string {
+	if e.value == nil {
+		return ""
+	}
+	return *e.value
+}
+
+func (e *enumValue) Choices() []string { return append([]string(nil), e.choices...) }
+
+func (e *enumValue) SetCaseInsensitive(fold bool) { e.fold = fold }
+
+func (e *enumValue) DescribeChoice(choice, usage string) {
+	if e.usage == nil {
+		e.usage = make(map[string]string)
+	}
+	e.usage[choice] = usage
+}
+
+func (e *enumValue) ChoiceUsage(choice string) string { return e.usage[choice] }
+
+// EnumVar defines a string flag with specified name, default value, allowed
+// choices, and usage string. The argument p points to a string variable in
+// which to store the value of the flag. Any value other than one of the
+// choices is rejected. The value of the flag implements EnumValue.
+func (f *FlagSet) EnumVar(p *string, name string, alias rune, value string, choices []string, usage string, fn Callback, opts ...Option) {
+	f.Var(newEnumValue(value, choices, p), name, alias, usage, fn, opts...)
+}
+
+// EnumVar defines a string flag with specified name, default value, allowed
+// choices, and usage string. The argument p points to a string variable in
+// which to store the value of the flag. Any value other than one of the
+// choices is rejected. The value of the flag implements EnumValue.
+func EnumVar(p *string, name string, alias rune, value string, choices []string, usage string, fn Callback, opts ...Option) {
+	CommandLine.Var(newEnumValue(value, choices, p), name, alias, usage, fn, opts...)
+}
+
+// Enum defines a string flag with specified name, default value, allowed
+// choices, and usage string. The return value is the address of a string
+// variable that stores the value of the flag. Any value other than one of
+// the choices is rejected. The value of the flag implements EnumValue.
+func (f *FlagSet) Enum(name string, alias rune, value string, choices []string, usage string, fn Callback, opts ...Option) *string {
+	p := new(string)
+	f.EnumVar(p, name, alias, value, choices, usage, fn, opts...)
+	return p
+}
+
+// Enum defines a string flag with specified name, default value, allowed
+// choices, and usage string. The return value is the address of a string
+// variable that stores the value of the flag. Any value other than one of
+// the choices is rejected. The value of the flag implements EnumValue.
+func Enum(name string, alias rune, value string, choices []string, usage string, fn Callback, opts ...Option) *string {
+	return CommandLine.Enum(name, alias, value, choices, usage, fn, opts...)
+}
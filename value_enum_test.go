@@ -0,0 +1,48 @@
+package flags_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+func TestEnumFlag(t *testing.T) {
+	fs := NewFlagSet("enum test", ContinueOnError, false)
+	fs.SetOutput(&bytes.Buffer{})
+	format := fs.Enum("format", 'f', "json", []string{"json", "yaml", "table"}, "output format", nil)
+
+	if err := fs.Parse([]string{"--format", "yaml"}); err != nil {
+		t.Fatal(err)
+	}
+	if *format != "yaml" {
+		t.Errorf("format = %q; want %q", *format, "yaml")
+	}
+
+	err := fs.Parse([]string{"-f", "YAML"})
+	if err == nil || !strings.Contains(err.Error(), "must be one of json, yaml, table") {
+		t.Errorf("expected error listing the choices; got %v", err)
+	}
+
+	fs.Lookup("format").Value.(EnumValue).SetCaseInsensitive(true)
+	if err := fs.Parse([]string{"-f", "TABLE"}); err != nil {
+		t.Fatal(err)
+	}
+	if *format != "table" {
+		t.Errorf("format = %q; want %q", *format, "table")
+	}
+}
+
+func TestEnumFlagUsage(t *testing.T) {
+	fs := NewFlagSet("enum test", ContinueOnError, false)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.Enum("format", 'f', "json", []string{"json", "yaml"}, "output format", nil)
+	fs.Lookup("format").Value.(EnumValue).DescribeChoice("yaml", "human readable")
+	fs.PrintDefaults()
+	want := "  -f, --format {json|yaml}\n    \toutput format (default json)\n    \t  yaml: human readable\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package flags
+
+import "strconv"
+
+// -- []float64 Value
+type float64SliceValue struct {
+	sliceValue
+	value *[]float64
+}
+
+func newFloat64SliceValue(val []float64, p *[]float64) *float64SliceValue {
+	*p = val
+	return &float64SliceValue{sliceValue: sliceValue{sep: ','}, value: p}
+}
+
+func (s *float64SliceValue) Set(val string) error {
+	elems, err := s.split(val)
+	if err != nil {
+		return err
+	}
+	vals := make([]float64, len(elems))
+	for i, e := range elems {
+		if err := newFloat64Value(0, &vals[i]).Set(e); err != nil {
+			return err
+		}
+	}
+	if s.replace() {
+		*s.value = vals
+	} else {
+		*s.value = append(*s.value, vals...)
+	}
+	return nil
+}
+
+func (s *float64SliceValue) Get() interface{} { return append([]float64(nil), *s.value...) }
+
+func (s *float64SliceValue) String() string {
+	if s.value == nil {
+		return "[]"
+	}
+	elems := make([]string, len(*s.value))
+	for i, v := range *s.value {
+		elems[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return s.join(elems)
+}
+
+// Float64SliceVar defines a []float64 flag with specified name, default value, and usage string.
+// The argument p points to a []float64 variable in which to store the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func (f *FlagSet) Float64SliceVar(p *[]float64, name string, alias rune, value []float64, usage string, fn Callback, opts ...Option) {
+	f.Var(newFloat64SliceValue(value, p), name, alias, usage, fn, opts...)
+}
+
+// Float64SliceVar defines a []float64 flag with specified name, default value, and usage string.
+// The argument p points to a []float64 variable in which to store the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func Float64SliceVar(p *[]float64, name string, alias rune, value []float64, usage string, fn Callback, opts ...Option) {
+	CommandLine.Var(newFloat64SliceValue(value, p), name, alias, usage, fn, opts...)
+}
+
+// Float64Slice defines a []float64 flag with specified name, default value, and usage string.
+// The return value is the address of a []float64 variable that stores the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func (f *FlagSet) Float64Slice(name string, alias rune, value []float64, usage string, fn Callback, opts ...Option) *[]float64 {
+	p := new([]float64)
+	f.Float64SliceVar(p, name, alias, value, usage, fn, opts...)
+	return p
+}
+
+// Float64Slice defines a []float64 flag with specified name, default value, and usage string.
+// The return value is the address of a []float64 variable that stores the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func Float64Slice(name string, alias rune, value []float64, usage string, fn Callback, opts ...Option) *[]float64 {
+	return CommandLine.Float64Slice(name, alias, value, usage, fn, opts...)
+}
@@ -29,26 +29,26 @@ func (i *intValue) String() string { return strconv.Itoa(int(*i)) }
 
 // IntVar defines an int flag with specified name, default value, and usage string.
 // The argument p points to an int variable in which to store the value of the flag.
-func (f *FlagSet) IntVar(p *int, name string, alias rune, value int, usage string, fn Callback) {
-	f.Var(newIntValue(value, p), name, alias, usage, fn)
+func (f *FlagSet) IntVar(p *int, name string, alias rune, value int, usage string, fn Callback, opts ...Option) {
+	define(f, p, name, alias, value, usage, fn, opts)
 }
 
 // IntVar defines an int flag with specified name, default value, and usage string.
 // The argument p points to an int variable in which to store the value of the flag.
-func IntVar(p *int, name string, alias rune, value int, usage string, fn Callback) {
-	CommandLine.Var(newIntValue(value, p), name, alias, usage, fn)
+func IntVar(p *int, name string, alias rune, value int, usage string, fn Callback, opts ...Option) {
+	define(CommandLine, p, name, alias, value, usage, fn, opts)
 }
 
 // Int defines an int flag with specified name, default value, and usage string.
 // The return value is the address of an int variable that stores the value of the flag.
-func (f *FlagSet) Int(name string, alias rune, value int, usage string, fn Callback) *int {
+func (f *FlagSet) Int(name string, alias rune, value int, usage string, fn Callback, opts ...Option) *int {
 	p := new(int)
-	f.IntVar(p, name, alias, value, usage, fn)
+	f.IntVar(p, name, alias, value, usage, fn, opts...)
 	return p
 }
 
 // Int defines an int flag with specified name, default value, and usage string.
 // The return value is the address of an int variable that stores the value of the flag.
-func Int(name string, alias rune, value int, usage string, fn Callback) *int {
-	return CommandLine.Int(name, alias, value, usage, fn)
+func Int(name string, alias rune, value int, usage string, fn Callback, opts ...Option) *int {
+	return CommandLine.Int(name, alias, value, usage, fn, opts...)
 }
@@ -9,11 +9,6 @@ import "strconv"
 // -- int64 Value
 type int64Value int64
 
-func newInt64Value(val int64, p *int64) *int64Value {
-	*p = val
-	return (*int64Value)(p)
-}
-
 func (i *int64Value) Set(s string) error {
 	v, err := strconv.ParseInt(s, 0, 64)
 	if err != nil {
@@ -29,26 +24,26 @@ func (i *int64Value) String() string { return strconv.FormatInt(int64(*i), 10) }
 
 // Int64Var defines an int64 flag with specified name, default value, and usage string.
 // The argument p points to an int64 variable in which to store the value of the flag.
-func (f *FlagSet) Int64Var(p *int64, name string, alias rune, value int64, usage string, fn Callback) {
-	f.Var(newInt64Value(value, p), name, alias, usage, fn)
+func (f *FlagSet) Int64Var(p *int64, name string, alias rune, value int64, usage string, fn Callback, opts ...Option) {
+	define(f, p, name, alias, value, usage, fn, opts)
 }
 
 // Int64Var defines an int64 flag with specified name, default value, and usage string.
 // The argument p points to an int64 variable in which to store the value of the flag.
-func Int64Var(p *int64, name string, alias rune, value int64, usage string, fn Callback) {
-	CommandLine.Var(newInt64Value(value, p), name, alias, usage, fn)
+func Int64Var(p *int64, name string, alias rune, value int64, usage string, fn Callback, opts ...Option) {
+	define(CommandLine, p, name, alias, value, usage, fn, opts)
 }
 
 // Int64 defines an int64 flag with specified name, default value, and usage string.
 // The return value is the address of an int64 variable that stores the value of the flag.
-func (f *FlagSet) Int64(name string, alias rune, value int64, usage string, fn Callback) *int64 {
+func (f *FlagSet) Int64(name string, alias rune, value int64, usage string, fn Callback, opts ...Option) *int64 {
 	p := new(int64)
-	f.Int64Var(p, name, alias, value, usage, fn)
+	f.Int64Var(p, name, alias, value, usage, fn, opts...)
 	return p
 }
 
 // Int64 defines an int64 flag with specified name, default value, and usage string.
 // The return value is the address of an int64 variable that stores the value of the flag.
-func Int64(name string, alias rune, value int64, usage string, fn Callback) *int64 {
-	return CommandLine.Int64(name, alias, value, usage, fn)
+func Int64(name string, alias rune, value int64, usage string, fn Callback, opts ...Option) *int64 {
+	return CommandLine.Int64(name, alias, value, usage, fn, opts...)
 }
@@ -0,0 +1,76 @@
+package flags
+
+import "strconv"
+
+// -- []int Value
+type intSliceValue struct {
+	sliceValue
+	value *[]int
+}
+
+func newIntSliceValue(val []int, p *[]int) *intSliceValue {
+	*p = val
+	return &intSliceValue{sliceValue: sliceValue{sep: ','}, value: p}
+}
+
+func (s *intSliceValue) Set(val string) error {
+	elems, err := s.split(val)
+	if err != nil {
+		return err
+	}
+	vals := make([]int, len(elems))
+	for i, e := range elems {
+		if err := newIntValue(0, &vals[i]).Set(e); err != nil {
+			return err
+		}
+	}
+	if s.replace() {
+		*s.value = vals
+	} else {
+		*s.value = append(*s.value, vals...)
+	}
+	return nil
+}
+
+func (s *intSliceValue) Get() interface{} { return append([]int(nil), *s.value...) }
+
+func (s *intSliceValue) String() string {
+	if s.value == nil {
+		return "[]"
+	}
+	elems := make([]string, len(*s.value))
+	for i, v := range *s.value {
+		elems[i] = strconv.Itoa(v)
+	}
+	return s.join(elems)
+}
+
+// IntSliceVar defines a []int flag with specified name, default value, and usage string.
+// The argument p points to a []int variable in which to store the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func (f *FlagSet) IntSliceVar(p *[]int, name string, alias rune, value []int, usage string, fn Callback, opts ...Option) {
+	f.Var(newIntSliceValue(value, p), name, alias, usage, fn, opts...)
+}
+
+// IntSliceVar defines a []int flag with specified name, default value, and usage string.
+// The argument p points to a []int variable in which to store the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func IntSliceVar(p *[]int, name string, alias rune, value []int, usage string, fn Callback, opts ...Option) {
+	CommandLine.Var(newIntSliceValue(value, p), name, alias, usage, fn, opts...)
+}
+
+// IntSlice defines a []int flag with specified name, default value, and usage string.
+// The return value is the address of a []int variable that stores the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func (f *FlagSet) IntSlice(name string, alias rune, value []int, usage string, fn Callback, opts ...Option) *[]int {
+	p := new([]int)
+	f.IntSliceVar(p, name, alias, value, usage, fn, opts...)
+	return p
+}
+
+// IntSlice defines a []int flag with specified name, default value, and usage string.
+// The return value is the address of a []int variable that stores the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func IntSlice(name string, alias rune, value []int, usage string, fn Callback, opts ...Option) *[]int {
+	return CommandLine.IntSlice(name, alias, value, usage, fn, opts...)
+}
@@ -0,0 +1,225 @@
+package flags
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DuplicateKeyPolicy selects how a map-valued flag treats a key that is
+// given more than once on the command line.
+type DuplicateKeyPolicy int
+
+// These constants select the behavior of a map-valued flag for duplicate keys.
+const (
+	LastKeyWins       DuplicateKeyPolicy = iota // The last value given for a key is kept.
+	DuplicateKeyError                           // Set returns an error naming the key.
+)
+
+// MapValue is the interface implemented by the map-valued flags of this
+// package, such as those defined by StringToString or MapVar.
+//
+// Each argument is a list of key=value pairs split on the separator, a
+// comma unless changed by SetSeparator, so that "--limit cpu=2,mem=4"
+// and "--limit cpu=2 --limit mem=4" are equivalent. The first Set
+// replaces the default value and later ones add to it. A backslash
+// escapes the character that follows it, so that keys may contain '='
+// and keys or values may contain the separator.
+type MapValue interface {
+	Getter
+	Separator() rune
+	SetSeparator(sep rune)
+	SetDuplicateKeyPolicy(policy DuplicateKeyPolicy)
+}
+
+// -- map[string]T Value
+type mapValue struct {
+	value   reflect.Value // the map variable, settable
+	sep     rune
+	policy  DuplicateKeyPolicy
+	changed bool
+	seen    map[string]bool
+}
+
+func newMapValue(p interface{}) *mapValue {
+	v := reflect.ValueOf(p)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Map ||
+		v.Elem().Type().Key().Kind() != reflect.String ||
+		newScalarValue(reflect.New(v.Elem().Type().Elem()).Interface()) == nil {
+		panic(fmt.Sprintf("flag: unsupported map type %T", p))
+	}
+	return &mapValue{value: v.Elem(), sep: ','}
+}
+
+func (m *mapValue) Separator() rune { return m.sep }
+
+func (m *mapValue) SetSeparator(sep rune) { m.sep = sep }
+
+func (m *mapValue) SetDuplicateKeyPolicy(policy DuplicateKeyPolicy) { m.policy = policy }
+
+func (m *mapValue) Set(val string) error {
+	typ := m.value.Type()
+	keys := []string{}
+	elems := []reflect.Value{}
+	for _, pair := range splitEscaped(val, m.sep) {
+		if pair == "" {
+			continue
+		}
+		kv := splitEscaped(pair, '=')
+		if len(kv) < 2 {
+			return fmt.Errorf("%q is not a key=value pair", unescape(pair))
+		}
+		key := unescape(kv[0])
+		elem := reflect.New(typ.Elem())
+		if err := newScalarValue(elem.Interface()).Set(unescape(pair[len(kv[0])+1:])); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		elems = append(elems, elem.Elem())
+	}
+
+	if m.seen == nil || !m.changed {
+		m.seen = make(map[string]bool)
+	}
+	if m.policy == DuplicateKeyError {
+		batch := make(map[string]bool)
+		for _, key := range keys {
+			if m.seen[key] || batch[key] {
+				return fmt.Errorf("duplicate key %q", key)
+			}
+			batch[key] = true
+		}
+	}
+
+	if !m.changed {
+		m.value.Set(reflect.MakeMap(typ))
+		m.changed = true
+	}
+	for i, key := range keys {
+		m.value.SetMapIndex(reflect.ValueOf(key), elems[i])
+		m.seen[key] = true
+	}
+	return nil
+}
+
+func (m *mapValue) Get() interface{} {
+	c := reflect.MakeMap(m.value.Type())
+	for _, key := range m.value.MapKeys() {
+		c.SetMapIndex(key, m.value.MapIndex(key))
+	}
+	return c.Interface()
+}
+
+func (m *mapValue) String() string {
+	if !m.value.IsValid() {
+		return "[]"
+	}
+	sep := m.sep
+	if sep == 0 {
+		sep = ','
+	}
+	keys := make([]string, 0, m.value.Len())
+	for _, key := range m.value.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		elem := reflect.New(m.value.Type().Elem())
+		elem.Elem().Set(m.value.MapIndex(reflect.ValueOf(key)))
+		pairs[i] = escape(key, "=", sep) + "=" + escape(newScalarValue(elem.Interface()).String(), "", sep)
+	}
+	return "[" + strings.Join(pairs, string(sep)) + "]"
+}
+
+// keepAsDefault makes the current contents the default, replaced by the
+// next Set.
+func (m *mapValue) keepAsDefault() {
+	m.changed = false
+	m.seen = nil
+}
+
+// pair returns the argument for which Set adds the single given entry.
+func (m *mapValue) pair(key, value string) string {
+	return escape(key, "=", m.sep) + "=" + escape(value, "", m.sep)
+}
+
+// typeName returns the placeholder shown by UnquoteUsage.
+func (m *mapValue) typeName() string {
+	name, _ := UnquoteUsage(&Flag{Value: newScalarValue(reflect.New(m.value.Type().Elem()).Interface())})
+	if name == "" {
+		name = "bool"
+	}
+	return "key=" + name
+}
+
+// splitEscaped splits s on every sep not preceded by a backslash. The
+// escapes are left in place so that the pieces can be split further.
+func splitEscaped(s string, sep rune) []string {
+	if sep == 0 {
+		return []string{s}
+	}
+	var parts []string
+	start, escaped := 0, false
+	for i, c := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == sep:
+			parts = append(parts, s[start:i])
+			start = i + len(string(sep))
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescape removes the backslash escapes from s.
+func unescape(s string) string {
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+	var b strings.Builder
+	escaped := false
+	for _, c := range s {
+		if c == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// escape backslash-escapes backslashes, the characters in chars and sep in s.
+func escape(s, chars string, sep rune) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c == '\\' || c == sep || strings.ContainsRune(chars, c) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// MapVar defines a map-valued flag with specified name and usage string.
+// The argument m points to a map[string]T variable in which to store the
+// values of the flag, where T is any type with a flag constructor in this
+// package, such as int, bool or time.Duration. The default value is the
+// initial contents of the map. The value of the flag implements MapValue.
+func (f *FlagSet) MapVar(m interface{}, name string, alias rune, usage string, fn Callback, opts ...Option) {
+	f.Var(newMapValue(m), name, alias, usage, fn, opts...)
+}
+
+// MapVar defines a map-valued flag with specified name and usage string.
+// The argument m points to a map[string]T variable in which to store the
+// values of the flag, where T is any type with a flag constructor in this
+// package, such as int, bool or time.Duration. The default value is the
+// initial contents of the map. The value of the flag implements MapValue.
+func MapVar(m interface{}, name string, alias rune, usage string, fn Callback, opts ...Option) {
+	CommandLine.Var(newMapValue(m), name, alias, usage, fn, opts...)
+}
@@ -0,0 +1,74 @@
+package flags_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/saihon/flags"
+)
+
+func TestMapFlags(t *testing.T) {
+	fs := NewFlagSet("map test", ContinueOnError, false)
+	labels := fs.StringToString("label", 'l', map[string]string{"default": "x"}, "labels", nil)
+	limits := fs.StringToInt("limit", 0, nil, "limits", nil)
+	timeouts := map[string]time.Duration{}
+	fs.MapVar(&timeouts, "timeout", 0, "timeouts", nil)
+
+	args := []string{
+		"--label", "env=prod", "-l", "team=core,a\\=b=c\\,d",
+		"--limit", "cpu=2,mem=4", "--limit=cpu=3",
+		"--timeout", "read=1s",
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"env": "prod", "team": "core", "a=b": "c,d"}; !reflect.DeepEqual(*labels, want) {
+		t.Errorf("labels = %v; want %v", *labels, want)
+	}
+	if want := map[string]int{"cpu": 3, "mem": 4}; !reflect.DeepEqual(*limits, want) {
+		t.Errorf("limits = %v; want %v", *limits, want)
+	}
+	if want := map[string]time.Duration{"read": time.Second}; !reflect.DeepEqual(timeouts, want) {
+		t.Errorf("timeouts = %v; want %v", timeouts, want)
+	}
+	if got, want := fs.Lookup("label").Value.String(), `[a\=b=c\,d,env=prod,team=core]`; got != want {
+		t.Errorf("String() = %s; want %s", got, want)
+	}
+}
+
+func TestMapFlagDuplicateKey(t *testing.T) {
+	fs := NewFlagSet("map test", ContinueOnError, false)
+	fs.SetOutput(&bytes.Buffer{})
+	fs.StringToInt("limit", 0, map[string]int{"cpu": 1}, "limits", nil)
+	fs.Lookup("limit").Value.(MapValue).SetDuplicateKeyPolicy(DuplicateKeyError)
+	err := fs.Parse([]string{"--limit", "cpu=2", "--limit", "cpu=3"})
+	if err == nil || !strings.Contains(err.Error(), `duplicate key "cpu"`) {
+		t.Errorf("expected duplicate key error; got %v", err)
+	}
+}
+
+func TestMapFlagError(t *testing.T) {
+	for _, arg := range []string{"--limit=cpu", "--limit=cpu=x"} {
+		fs := NewFlagSet("map test", ContinueOnError, false)
+		fs.SetOutput(&bytes.Buffer{})
+		fs.StringToInt("limit", 0, nil, "limits", nil)
+		if err := fs.Parse([]string{arg}); err == nil {
+			t.Errorf("Parse(%q) succeeded; expected error", arg)
+		}
+	}
+}
+
+func TestMapFlagUsage(t *testing.T) {
+	fs := NewFlagSet("map test", ContinueOnError, false)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.StringToInt("limit", 0, map[string]int{"mem": 4, "cpu": 2}, "resource limits", nil)
+	fs.PrintDefaults()
+	want := "  --limit key=int\n    \tresource limits (default [cpu=2,mem=4])\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package flags
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+)
+
+// SliceValue is the interface implemented by the slice-valued flags of
+// this package, such as those defined by StringSlice or IntSlice.
+//
+// The first Set replaces the default value and every later Set appends
+// to it, so that "--tag a --tag b,c" yields [a b c]. Each argument is
+// split on the separator, a comma unless changed by SetSeparator.
+// Elements may be double-quoted as in CSV to contain the separator:
+// --tag '"a,b",c' yields [a,b c]. A separator of 0 disables splitting.
+type SliceValue interface {
+	Getter
+	Separator() rune
+	SetSeparator(sep rune)
+}
+
+// sliceValue holds the state shared by all slice-valued flags.
+type sliceValue struct {
+	sep     rune
+	changed bool
+}
+
+func (s *sliceValue) Separator() rune { return s.sep }
+
+func (s *sliceValue) SetSeparator(sep rune) { s.sep = sep }
+
+// split splits val into its elements. Quoting is only honored when the
+// value contains a double quote, so plain values are split verbatim.
+func (s *sliceValue) split(val string) ([]string, error) {
+	if val == "" {
+		return nil, nil
+	}
+	if s.sep == 0 {
+		return []string{val}, nil
+	}
+	if !strings.ContainsRune(val, '"') {
+		return strings.Split(val, string(s.sep)), nil
+	}
+	r := csv.NewReader(strings.NewReader(val))
+	r.Comma = s.sep
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, errParse
+	}
+	if len(records) != 1 {
+		return nil, errParse
+	}
+	return records[0], nil
+}
+
+// join formats elements so that split reads them back unchanged.
+func (s *sliceValue) join(elems []string) string {
+	sep := s.sep
+	if sep == 0 {
+		sep = ','
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = sep
+	if err := w.Write(elems); err != nil {
+		return "[" + strings.Join(elems, string(sep)) + "]"
+	}
+	w.Flush()
+	return "[" + strings.TrimSuffix(buf.String(), "\n") + "]"
+}
+
+// replace reports whether parsed elements should replace the current
+// contents rather than being appended to them, and records the change.
+func (s *sliceValue) replace() bool {
+	if s.changed {
+		return false
+	}
+	s.changed = true
+	return true
+}
+
+// keepAsDefault makes the current contents the default, replaced by the
+// next Set.
+func (s *sliceValue) keepAsDefault() { s.changed = false }
+
+// literal returns elem quoted, if necessary, so that Set adds it as a
+// single element instead of splitting it.
+func (s *sliceValue) literal(elem string) string {
+	if s.sep == 0 || !strings.ContainsRune(elem, s.sep) && !strings.ContainsRune(elem, '"') {
+		return elem
+	}
+	return `"` + strings.ReplaceAll(elem, `"`, `""`) + `"`
+}
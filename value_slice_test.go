@@ -0,0 +1,84 @@
+package flags_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/saihon/flags"
+)
+
+func TestSliceFlags(t *testing.T) {
+	fs := NewFlagSet("slice test", ContinueOnError, false)
+	tags := fs.StringSlice("tag", 't', []string{"default"}, "tags", nil)
+	ints := fs.IntSlice("int", 'i', nil, "ints", nil)
+	floats := fs.Float64Slice("float", 0, nil, "floats", nil)
+	durations := fs.DurationSlice("duration", 0, nil, "durations", nil)
+
+	args := []string{
+		"--tag", "a", "-t", "b,c", "--tag", `"d,e",f`,
+		"--int=1,2", "-i", "3",
+		"--float", "1.5,2",
+		"--duration", "1s", "--duration", "2m,3h",
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c", "d,e", "f"}; !reflect.DeepEqual(*tags, want) {
+		t.Errorf("tags = %q; want %q", *tags, want)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(*ints, want) {
+		t.Errorf("ints = %v; want %v", *ints, want)
+	}
+	if want := []float64{1.5, 2}; !reflect.DeepEqual(*floats, want) {
+		t.Errorf("floats = %v; want %v", *floats, want)
+	}
+	if want := []time.Duration{time.Second, 2 * time.Minute, 3 * time.Hour}; !reflect.DeepEqual(*durations, want) {
+		t.Errorf("durations = %v; want %v", *durations, want)
+	}
+	if got, want := fs.Lookup("tag").Value.String(), `[a,b,c,"d,e",f]`; got != want {
+		t.Errorf("String() = %s; want %s", got, want)
+	}
+}
+
+func TestSliceFlagSeparator(t *testing.T) {
+	fs := NewFlagSet("slice test", ContinueOnError, false)
+	paths := fs.StringSlice("path", 0, nil, "paths", nil)
+	fs.Lookup("path").Value.(SliceValue).SetSeparator(':')
+	if err := fs.Parse([]string{"--path", "/bin:/usr/bin", "--path=a,b"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/bin", "/usr/bin", "a,b"}; !reflect.DeepEqual(*paths, want) {
+		t.Errorf("paths = %q; want %q", *paths, want)
+	}
+}
+
+func TestSliceFlagError(t *testing.T) {
+	fs := NewFlagSet("slice test", ContinueOnError, false)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	ints := fs.IntSlice("int", 0, []int{7}, "ints", nil)
+	err := fs.Parse([]string{"--int", "1,x"})
+	if err == nil || !strings.Contains(err.Error(), "parse error") {
+		t.Fatalf("expected parse error; got %v", err)
+	}
+	if want := []int{7}; !reflect.DeepEqual(*ints, want) {
+		t.Errorf("ints = %v; want %v", *ints, want)
+	}
+}
+
+func TestSliceFlagUsage(t *testing.T) {
+	fs := NewFlagSet("slice test", ContinueOnError, false)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.StringSlice("tag", 't', []string{"a", "b"}, "add a tag", nil)
+	fs.IntSlice("port", 0, nil, "listen `port`s", nil)
+	fs.PrintDefaults()
+	want := "  --port port\n    \tlisten ports\n" +
+		"  -t, --tag strings\n    \tadd a tag (default [a,b])\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
@@ -7,11 +7,6 @@ package flags
 // -- string Value
 type stringValue string
 
-func newStringValue(val string, p *string) *stringValue {
-	*p = val
-	return (*stringValue)(p)
-}
-
 func (s *stringValue) Set(val string) error {
 	*s = stringValue(val)
 	return nil
@@ -23,26 +18,26 @@ func (s *stringValue) String() string { return string(*s) }
 
 // StringVar defines a string flag with specified name, default value, and usage string.
 // The argument p points to a string variable in which to store the value of the flag.
-func (f *FlagSet) StringVar(p *string, name string, alias rune, value string, usage string, fn Callback) {
-	f.Var(newStringValue(value, p), name, alias, usage, fn)
+func (f *FlagSet) StringVar(p *string, name string, alias rune, value string, usage string, fn Callback, opts ...Option) {
+	define(f, p, name, alias, value, usage, fn, opts)
 }
 
 // StringVar defines a string flag with specified name, default value, and usage string.
 // The argument p points to a string variable in which to store the value of the flag.
-func StringVar(p *string, name string, alias rune, value string, usage string, fn Callback) {
-	CommandLine.Var(newStringValue(value, p), name, alias, usage, fn)
+func StringVar(p *string, name string, alias rune, value string, usage string, fn Callback, opts ...Option) {
+	define(CommandLine, p, name, alias, value, usage, fn, opts)
 }
 
 // String defines a string flag with specified name, default value, and usage string.
 // The return value is the address of a string variable that stores the value of the flag.
-func (f *FlagSet) String(name string, alias rune, value string, usage string, fn Callback) *string {
+func (f *FlagSet) String(name string, alias rune, value string, usage string, fn Callback, opts ...Option) *string {
 	p := new(string)
-	f.StringVar(p, name, alias, value, usage, fn)
+	f.StringVar(p, name, alias, value, usage, fn, opts...)
 	return p
 }
 
 // String defines a string flag with specified name, default value, and usage string.
 // The return value is the address of a string variable that stores the value of the flag.
-func String(name string, alias rune, value string, usage string, fn Callback) *string {
-	return CommandLine.String(name, alias, value, usage, fn)
+func String(name string, alias rune, value string, usage string, fn Callback, opts ...Option) *string {
+	return CommandLine.String(name, alias, value, usage, fn, opts...)
 }
@@ -0,0 +1,64 @@
+package flags
+
+// -- []string Value
+type stringSliceValue struct {
+	sliceValue
+	value *[]string
+}
+
+func newStringSliceValue(val []string, p *[]string) *stringSliceValue {
+	*p = val
+	return &stringSliceValue{sliceValue: sliceValue{sep: ','}, value: p}
+}
+
+func (s *stringSliceValue) Set(val string) error {
+	elems, err := s.split(val)
+	if err != nil {
+		return err
+	}
+	if s.replace() {
+		*s.value = elems
+	} else {
+		*s.value = append(*s.value, elems...)
+	}
+	return nil
+}
+
+func (s *stringSliceValue) Get() interface{} { return append([]string(nil), *s.value...) }
+
+func (s *stringSliceValue) String() string {
+	if s.value == nil {
+		return "[]"
+	}
+	return s.join(*s.value)
+}
+
+// StringSliceVar defines a []string flag with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func (f *FlagSet) StringSliceVar(p *[]string, name string, alias rune, value []string, usage string, fn Callback, opts ...Option) {
+	f.Var(newStringSliceValue(value, p), name, alias, usage, fn, opts...)
+}
+
+// StringSliceVar defines a []string flag with specified name, default value, and usage string.
+// The argument p points to a []string variable in which to store the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func StringSliceVar(p *[]string, name string, alias rune, value []string, usage string, fn Callback, opts ...Option) {
+	CommandLine.Var(newStringSliceValue(value, p), name, alias, usage, fn, opts...)
+}
+
+// StringSlice defines a []string flag with specified name, default value, and usage string.
+// The return value is the address of a []string variable that stores the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func (f *FlagSet) StringSlice(name string, alias rune, value []string, usage string, fn Callback, opts ...Option) *[]string {
+	p := new([]string)
+	f.StringSliceVar(p, name, alias, value, usage, fn, opts...)
+	return p
+}
+
+// StringSlice defines a []string flag with specified name, default value, and usage string.
+// The return value is the address of a []string variable that stores the values of the flag.
+// The flag may be repeated and accepts separated lists; see SliceValue.
+func StringSlice(name string, alias rune, value []string, usage string, fn Callback, opts ...Option) *[]string {
+	return CommandLine.StringSlice(name, alias, value, usage, fn, opts...)
+}
@@ -0,0 +1,32 @@
+package flags
+
+// StringToIntVar defines a map[string]int flag with specified name, default value, and usage string.
+// The argument p points to a map[string]int variable in which to store the values of the flag.
+// The flag accepts key=value pairs and may be repeated; see MapValue.
+func (f *FlagSet) StringToIntVar(p *map[string]int, name string, alias rune, value map[string]int, usage string, fn Callback, opts ...Option) {
+	*p = value
+	f.Var(newMapValue(p), name, alias, usage, fn, opts...)
+}
+
+// StringToIntVar defines a map[string]int flag with specified name, default value, and usage string.
+// The argument p points to a map[string]int variable in which to store the values of the flag.
+// The flag accepts key=value pairs and may be repeated; see MapValue.
+func StringToIntVar(p *map[string]int, name string, alias rune, value map[string]int, usage string, fn Callback, opts ...Option) {
+	CommandLine.StringToIntVar(p, name, alias, value, usage, fn, opts...)
+}
+
+// StringToInt defines a map[string]int flag with specified name, default value, and usage string.
+// The return value is the address of a map[string]int variable that stores the values of the flag.
+// The flag accepts key=value pairs and may be repeated; see MapValue.
+func (f *FlagSet) StringToInt(name string, alias rune, value map[string]int, usage string, fn Callback, opts ...Option) *map[string]int {
+	p := new(map[string]int)
+	f.StringToIntVar(p, name, alias, value, usage, fn, opts...)
+	return p
+}
+
+// StringToInt defines a map[string]int flag with specified name, default value, and usage string.
+// The return value is the address of a map[string]int variable that stores the values of the flag.
+// The flag accepts key=value pairs and may be repeated; see MapValue.
+func StringToInt(name string, alias rune, value map[string]int, usage string, fn Callback, opts ...Option) *map[string]int {
+	return CommandLine.StringToInt(name, alias, value, usage, fn, opts...)
+}
@@ -0,0 +1,32 @@
+package flags
+
+// StringToStringVar defines a map[string]string flag with specified name, default value, and usage string.
+// The argument p points to a map[string]string variable in which to store the values of the flag.
+// The flag accepts key=value pairs and may be repeated; see MapValue.
+func (f *FlagSet) StringToStringVar(p *map[string]string, name string, alias rune, value map[string]string, usage string, fn Callback, opts ...Option) {
+	*p = value
+	f.Var(newMapValue(p), name, alias, usage, fn, opts...)
+}
+
+// StringToStringVar defines a map[string]string flag with specified name, default value, and usage string.
+// The argument p points to a map[string]string variable in which to store the values of the flag.
+// The flag accepts key=value pairs and may be repeated; see MapValue.
+func StringToStringVar(p *map[string]string, name string, alias rune, value map[string]string, usage string, fn Callback, opts ...Option) {
+	CommandLine.StringToStringVar(p, name, alias, value, usage, fn, opts...)
+}
+
+// StringToString defines a map[string]string flag with specified name, default value, and usage string.
+// The return value is the address of a map[string]string variable that stores the values of the flag.
+// The flag accepts key=value pairs and may be repeated; see MapValue.
+func (f *FlagSet) StringToString(name string, alias rune, value map[string]string, usage string, fn Callback, opts ...Option) *map[string]string {
+	p := new(map[string]string)
+	f.StringToStringVar(p, name, alias, value, usage, fn, opts...)
+	return p
+}
+
+// StringToString defines a map[string]string flag with specified name, default value, and usage string.
+// The return value is the address of a map[string]string variable that stores the values of the flag.
+// The flag accepts key=value pairs and may be repeated; see MapValue.
+func StringToString(name string, alias rune, value map[string]string, usage string, fn Callback, opts ...Option) *map[string]string {
+	return CommandLine.StringToString(name, alias, value, usage, fn, opts...)
+}
@@ -0,0 +1,63 @@
+package flags
+
+import "time"
+
+// timeLayouts are the layouts accepted by time flags, tried in order.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999", // local date-time
+	"2006-01-02",                    // local date
+	"15:04:05.999999999",            // local time
+}
+
+// -- time.Time Value
+type timeValue time.Time
+
+func (t *timeValue) Set(s string) error {
+	for _, layout := range timeLayouts {
+		if v, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			*t = timeValue(v)
+			return nil
+		}
+	}
+	return errParse
+}
+
+func (t *timeValue) Get() interface{} { return time.Time(*t) }
+
+func (t *timeValue) String() string {
+	if (*time.Time)(t).IsZero() {
+		return ""
+	}
+	return (*time.Time)(t).Format(time.RFC3339Nano)
+}
+
+// TimeVar defines a time.Time flag with specified name, default value, and usage string.
+// The argument p points to a time.Time variable in which to store the value of the flag.
+// The flag accepts RFC 3339 date-times, and local date-times, dates and times.
+func (f *FlagSet) TimeVar(p *time.Time, name string, alias rune, value time.Time, usage string, fn Callback, opts ...Option) {
+	define(f, p, name, alias, value, usage, fn, opts)
+}
+
+// TimeVar defines a time.Time flag with specified name, default value, and usage string.
+// The argument p points to a time.Time variable in which to store the value of the flag.
+// The flag accepts RFC 3339 date-times, and local date-times, dates and times.
+func TimeVar(p *time.Time, name string, alias rune, value time.Time, usage string, fn Callback, opts ...Option) {
+	define(CommandLine, p, name, alias, value, usage, fn, opts)
+}
+
+// Time defines a time.Time flag with specified name, default value, and usage string.
+// The return value is the address of a time.Time variable that stores the value of the flag.
+// The flag accepts RFC 3339 date-times, and local date-times, dates and times.
+func (f *FlagSet) Time(name string, alias rune, value time.Time, usage string, fn Callback, opts ...Option) *time.Time {
+	p := new(time.Time)
+	f.TimeVar(p, name, alias, value, usage, fn, opts...)
+	return p
+}
+
+// Time defines a time.Time flag with specified name, default value, and usage string.
+// The return value is the address of a time.Time variable that stores the value of the flag.
+// The flag accepts RFC 3339 date-times, and local date-times, dates and times.
+func Time(name string, alias rune, value time.Time, usage string, fn Callback, opts ...Option) *time.Time {
+	return CommandLine.Time(name, alias, value, usage, fn, opts...)
+}
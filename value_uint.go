@@ -9,11 +9,6 @@ import "strconv"
 // -- uint Value
 type uintValue uint
 
-func newUintValue(val uint, p *uint) *uintValue {
-	*p = val
-	return (*uintValue)(p)
-}
-
 func (i *uintValue) Set(s string) error {
 	v, err := strconv.ParseUint(s, 0, strconv.IntSize)
 	if err != nil {
@@ -29,26 +24,26 @@ func (i *uintValue) String() string { return strconv.FormatUint(uint64(*i), 10)
 
 // UintVar defines a uint flag with specified name, default value, and usage string.
 // The argument p points to a uint variable in which to store the value of the flag.
-func (f *FlagSet) UintVar(p *uint, name string, alias rune, value uint, usage string, fn Callback) {
-	f.Var(newUintValue(value, p), name, alias, usage, fn)
+func (f *FlagSet) UintVar(p *uint, name string, alias rune, value uint, usage string, fn Callback, opts ...Option) {
+	define(f, p, name, alias, value, usage, fn, opts)
 }
 
 // UintVar defines a uint flag with specified name, default value, and usage string.
 // The argument p points to a uint variable in which to store the value of the flag.
-func UintVar(p *uint, name string, alias rune, value uint, usage string, fn Callback) {
-	CommandLine.Var(newUintValue(value, p), name, alias, usage, fn)
+func UintVar(p *uint, name string, alias rune, value uint, usage string, fn Callback, opts ...Option) {
+	define(CommandLine, p, name, alias, value, usage, fn, opts)
 }
 
 // Uint defines a uint flag with specified name, default value, and usage string.
 // The return value is the address of a uint variable that stores the value of the flag.
-func (f *FlagSet) Uint(name string, alias rune, value uint, usage string, fn Callback) *uint {
+func (f *FlagSet) Uint(name string, alias rune, value uint, usage string, fn Callback, opts ...Option) *uint {
 	p := new(uint)
-	f.UintVar(p, name, alias, value, usage, fn)
+	f.UintVar(p, name, alias, value, usage, fn, opts...)
 	return p
 }
 
 // Uint defines a uint flag with specified name, default value, and usage string.
 // The return value is the address of a uint variable that stores the value of the flag.
-func Uint(name string, alias rune, value uint, usage string, fn Callback) *uint {
-	return CommandLine.Uint(name, alias, value, usage, fn)
+func Uint(name string, alias rune, value uint, usage string, fn Callback, opts ...Option) *uint {
+	return CommandLine.Uint(name, alias, value, usage, fn, opts...)
 }
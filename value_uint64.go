@@ -9,11 +9,6 @@ import "strconv"
 // -- uint64 Value
 type uint64Value uint64
 
-func newUint64Value(val uint64, p *uint64) *uint64Value {
-	*p = val
-	return (*uint64Value)(p)
-}
-
 func (i *uint64Value) Set(s string) error {
 	v, err := strconv.ParseUint(s, 0, 64)
 	if err != nil {
@@ -29,26 +24,26 @@ func (i *uint64Value) String() string { return strconv.FormatUint(uint64(*i), 10
 
 // Uint64Var defines a uint64 flag with specified name, default value, and usage string.
 // The argument p points to a uint64 variable in which to store the value of the flag.
-func (f *FlagSet) Uint64Var(p *uint64, name string, alias rune, value uint64, usage string, fn Callback) {
-	f.Var(newUint64Value(value, p), name, alias, usage, fn)
+func (f *FlagSet) Uint64Var(p *uint64, name string, alias rune, value uint64, usage string, fn Callback, opts ...Option) {
+	define(f, p, name, alias, value, usage, fn, opts)
 }
 
 // Uint64Var defines a uint64 flag with specified name, default value, and usage string.
 // The argument p points to a uint64 variable in which to store the value of the flag.
-func Uint64Var(p *uint64, name string, alias rune, value uint64, usage string, fn Callback) {
-	CommandLine.Var(newUint64Value(value, p), name, alias, usage, fn)
+func Uint64Var(p *uint64, name string, alias rune, value uint64, usage string, fn Callback, opts ...Option) {
+	define(CommandLine, p, name, alias, value, usage, fn, opts)
 }
 
 // Uint64 defines a uint64 flag with specified name, default value, and usage string.
 // The return value is the address of a uint64 variable that stores the value of the flag.
-func (f *FlagSet) Uint64(name string, alias rune, value uint64, usage string, fn Callback) *uint64 {
+func (f *FlagSet) Uint64(name string, alias rune, value uint64, usage string, fn Callback, opts ...Option) *uint64 {
 	p := new(uint64)
-	f.Uint64Var(p, name, alias, value, usage, fn)
+	f.Uint64Var(p, name, alias, value, usage, fn, opts...)
 	return p
 }
 
 // Uint64 defines a uint64 flag with specified name, default value, and usage string.
 // The return value is the address of a uint64 variable that stores the value of the flag.
-func Uint64(name string, alias rune, value uint64, usage string, fn Callback) *uint64 {
-	return CommandLine.Uint64(name, alias, value, usage, fn)
+func Uint64(name string, alias rune, value uint64, usage string, fn Callback, opts ...Option) *uint64 {
+	return CommandLine.Uint64(name, alias, value, usage, fn, opts...)
 }
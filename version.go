@@ -0,0 +1,114 @@
+package flags
+
+import (
+	"errors"
+	"runtime"
+	"runtime/debug"
+	"text/template"
+)
+
+// ErrVersion is the error returned by Parse after it printed the version
+// because the flag defined by VersionFlag was given.
+var ErrVersion = errors.New("flag: version printed")
+
+// DefaultVersionTemplate is the template used by PrintVersion if the
+// VersionTemplate of the flag set is empty. It prints a line such as
+// "prog v1.2.0 (3f2c1a9e, modified) go1.22.1".
+const DefaultVersionTemplate = `{{.Program}} {{.Version}}` +
+	`{{with .Revision}} ({{.}}{{if $.Modified}}, modified{{end}}){{end}}` +
+	` {{.GoVersion}}` + "\n"
+
+// VersionInfo is the data passed to the version template.
+type VersionInfo struct {
+	Program   string // name of the flag set
+	Version   string // version given to VersionFlag, or else of the main module
+	Revision  string // VCS revision the program was built from, if known
+	Time      string // commit time of the revision, in RFC 3339 format
+	Modified  bool   // the working tree had uncommitted changes
+	GoVersion string // version of Go the program was built with
+}
+
+// readBuildInfo is replaced by tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// VersionFlag defines the flag --version, with alias -V. If it is set to
+// true on the command line, Parse prints the version with PrintVersion
+// and returns ErrVersion without looking at the remaining arguments; with
+// ExitOnError the program exits with status 0. If version is empty, the
+// version of the main module recorded by the go command is printed.
+func (f *FlagSet) VersionFlag(version string) {
+	f.Bool("version", 'V', false, "print version information and exit", nil)
+	f.version = f.formal["version"]
+	f.versionText = version
+}
+
+// VersionFlag defines the command-line flag --version. See
+// FlagSet.VersionFlag.
+func VersionFlag(version string) {
+	CommandLine.VersionFlag(version)
+}
+
+// Version returns the version information of the program, taken from
+// the build information embedded by the go command.
+func (f *FlagSet) Version() VersionInfo {
+	info := VersionInfo{
+		Program:   f.name,
+		Version:   f.versionText,
+		GoVersion: runtime.Version(),
+	}
+	if bi, ok := readBuildInfo(); ok {
+		if info.Version == "" {
+			info.Version = bi.Main.Version
+		}
+		if bi.GoVersion != "" {
+			info.GoVersion = bi.GoVersion
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Revision = s.Value
+			case "vcs.time":
+				info.Time = s.Value
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "(devel)"
+	}
+	return info
+}
+
+// PrintVersion prints, to standard error unless configured otherwise, the
+// version information returned by Version, formatted with VersionTemplate
+// or else DefaultVersionTemplate.
+func (f *FlagSet) PrintVersion() error {
+	text := f.VersionTemplate
+	if text == "" {
+		text = DefaultVersionTemplate
+	}
+	tmpl, err := template.New("version").Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(f.Output(), f.Version())
+}
+
+// PrintVersion prints the version of the program. See
+// FlagSet.PrintVersion.
+func PrintVersion() error {
+	return CommandLine.PrintVersion()
+}
+
+// checkVersion prints the version and returns ErrVersion if flag is the
+// version flag of f and was set to true.
+func (f *FlagSet) checkVersion(flag *Flag) error {
+	if f.version == nil || flag != f.version || !isTrue(flag) {
+		return nil
+	}
+	if err := f.PrintVersion(); err != nil {
+		return f.failf("cannot print version: %v", err)
+	}
+	return ErrVersion
+}
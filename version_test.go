@@ -0,0 +1,95 @@
+package flags_test
+
+import (
+	"bytes"
+	"runtime/debug"
+	"testing"
+
+	. "github.com/saihon/flags"
+)
+
+var testBuildInfo = &debug.BuildInfo{
+	GoVersion: "go1.22.1",
+	Main:      debug.Module{Path: "example.com/prog", Version: "v1.2.0"},
+	Settings: []debug.BuildSetting{
+		{Key: "vcs", Value: "git"},
+		{Key: "vcs.revision", Value: "3f2c1a9e"},
+		{Key: "vcs.time", Value: "2024-03-01T10:00:00Z"},
+		{Key: "vcs.modified", Value: "true"},
+	},
+}
+
+func TestVersionFlag(t *testing.T) {
+	defer SetBuildInfo(testBuildInfo)()
+
+	data := []struct {
+		args    []string
+		version string
+		want    string
+	}{
+		{[]string{"--version"}, "", "prog v1.2.0 (3f2c1a9e, modified) go1.22.1\n"},
+		{[]string{"-vV", "--bogus"}, "", "prog v1.2.0 (3f2c1a9e, modified) go1.22.1\n"},
+		{[]string{"-V"}, "2.0.0-rc1", "prog 2.0.0-rc1 (3f2c1a9e, modified) go1.22.1\n"},
+	}
+	for _, v := range data {
+		fs := NewFlagSet("prog", ContinueOnError, false)
+		var buf bytes.Buffer
+		fs.SetOutput(&buf)
+		fs.Bool("verbose", 'v', false, "", nil)
+		fs.Int("port", 0, 0, "", nil, Required())
+		fs.VersionFlag(v.version)
+
+		err := fs.Parse(v.args)
+		if err != ErrVersion || !IsIgnorableError(err) {
+			t.Errorf("%q: got %v; want ErrVersion", v.args, err)
+		}
+		if got := buf.String(); got != v.want {
+			t.Errorf("%q: got %q want %q", v.args, got, v.want)
+		}
+	}
+}
+
+func TestVersionFlagFalse(t *testing.T) {
+	defer SetBuildInfo(testBuildInfo)()
+
+	for _, args := range [][]string{{"--version=false"}, {"-V=false"}, {"--no-version"}} {
+		fs := NewFlagSet("prog", ContinueOnError, false)
+		fs.Negatable = true
+		var buf bytes.Buffer
+		fs.SetOutput(&buf)
+		fs.VersionFlag("")
+
+		if err := fs.Parse(args); err != nil || buf.Len() != 0 {
+			t.Errorf("%q: got %v, output %q; want neither", args, err, buf.String())
+		}
+	}
+}
+
+func TestVersionTemplate(t *testing.T) {
+	fs := NewFlagSet("prog", ContinueOnError, false)
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.VersionFlag("")
+
+	restore := SetBuildInfo(nil)
+	info := fs.Version()
+	restore()
+	if info.Version != "(devel)" || info.Revision != "" || info.Modified {
+		t.Errorf("without build info: got %+v", info)
+	}
+
+	defer SetBuildInfo(testBuildInfo)()
+	fs.VersionTemplate = "{{.Version}} {{.Revision}} {{.Time}}{{if .Modified}} dirty{{end}}"
+	if err := fs.PrintVersion(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "v1.2.0 3f2c1a9e 2024-03-01T10:00:00Z dirty"; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	buf.Reset()
+	fs.VersionTemplate = "{{.Version"
+	if err := fs.Parse([]string{"--version"}); err == nil || err == ErrVersion {
+		t.Errorf("got %v; want a template error", err)
+	}
+}